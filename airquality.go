@@ -0,0 +1,143 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// airQualityQuery is the stored query of hourly urban air quality
+// observations
+const airQualityQuery = "urban::observations::airquality::hourly::simple"
+
+// airQualityWindow is how far back the latest air quality observations
+// are searched. Hourly averages are published with a delay.
+const airQualityWindow = 3 * time.Hour
+
+// AirQuality holds the air quality observed at a station as hourly averages
+type AirQuality struct {
+	Station Station   `json:"station"`
+	Time    time.Time `json:"time"`
+
+	Index Value `json:"index"` // Finnish air quality index, 1 (good) to 5 (very poor)
+	PM25  Value `json:"pm25"`  // µg/m3, fine particles
+	PM10  Value `json:"pm10"`  // µg/m3, inhalable particles
+	NO2   Value `json:"no2"`   // µg/m3, nitrogen dioxide
+	O3    Value `json:"o3"`    // µg/m3, ozone
+}
+
+// airQualityParameters maps FMI's air quality parameters to AirQuality
+// fields
+var airQualityParameters = map[string]func(*AirQuality) *Value{
+	"AQINDEX_PT1H_avg": func(a *AirQuality) *Value { return &a.Index },
+	"PM25_PT1H_avg":    func(a *AirQuality) *Value { return &a.PM25 },
+	"PM10_PT1H_avg":    func(a *AirQuality) *Value { return &a.PM10 },
+	"NO2_PT1H_avg":     func(a *AirQuality) *Value { return &a.NO2 },
+	"O3_PT1H_avg":      func(a *AirQuality) *Value { return &a.O3 },
+}
+
+var airQualityMeasures = []string{"AQINDEX_PT1H_avg", "PM25_PT1H_avg", "PM10_PT1H_avg", "NO2_PT1H_avg", "O3_PT1H_avg"}
+
+// AirQuality returns the latest air quality observations for a place
+func (c *Client) AirQuality(ctx context.Context, place string) (AirQuality, error) {
+	return c.AirQualityAt(ctx, Place(place))
+}
+
+// AirQualityAt returns the latest air quality observations of the station
+// nearest to a location. Time is the newest hour with an air quality index
+// and all values are of that hour, as the index is computed from them.
+func (c *Client) AirQualityAt(ctx context.Context, loc Location) (AirQuality, error) {
+	r, station, err := c.fetchLatestOf(ctx, loc, airQualityQuery, airQualityMeasures, airQualityWindow, time.Hour, AirQualityStation, extractNewestIndex)
+	if err != nil {
+		return AirQuality{}, err
+	}
+
+	a := AirQuality{Station: station, Time: r.Time}
+	setValues(&a, r.Values, airQualityParameters)
+	return a, nil
+}
+
+// extractNewestIndex returns the newest row with an air quality index of
+// the nearest station reporting one, or the newest row with measurements
+// if no station does
+func extractNewestIndex(collection simpleFeatureCollection, measures []string) (row, bool) {
+	times, locations, observations := groupElements(collection)
+
+	for _, locationIndex := range locations {
+		for _, timeIndex := range times {
+			obs, ok := observations[timeIndex][locationIndex]
+			if index, found := obs["AQINDEX_PT1H_avg"]; ok && found && !math.IsNaN(index) {
+				return row{Location: locationIndex, Time: timeIndex, Values: obs, Quality: collection.Quality[locationIndex][timeIndex]}, true
+			}
+		}
+	}
+
+	return extractNearestObservations(collection, measures)
+}
+
+// AirQualitySummary returns the latest air quality for a place as a
+// written description in Finnish
+func (c *Client) AirQualitySummary(ctx context.Context, place string) (string, error) {
+	if place == "" {
+		return "", ErrNoPlace
+	}
+
+	a, err := c.AirQuality(ctx, place)
+	if err != nil {
+		return "", err
+	}
+
+	return formatAirQuality(place, a), nil
+}
+
+// airQualityClasses names the classes of the air quality index
+var airQualityClasses = [...]string{"hyvä", "tyydyttävä", "kohtalainen", "huono", "erittäin huono"}
+
+// airQualityClass converts an air quality index to a class from 1 (good)
+// to 5 (very poor), or -1 if the index is invalid
+func airQualityClass(index float64) int {
+	if math.IsNaN(index) || index < 0.5 {
+		return -1
+	}
+	return min(int(math.Round(index)), len(airQualityClasses))
+}
+
+// formatAirQuality returns a string representation of air quality at a
+// place in Finnish
+func formatAirQuality(place string, a AirQuality) string {
+	var output strings.Builder
+
+	c := cases.Title(language.Finnish)
+
+	fmt.Fprintf(&output, "Ilmanlaatu paikassa %s: ", c.String(strings.ToLower(place)))
+
+	if class := airQualityClass(a.Index.Value); a.Index.Valid && class > 0 {
+		fmt.Fprintf(&output, "ilmanlaatu %s (indeksi %d)", airQualityClasses[class-1], class)
+	} else {
+		output.WriteString("ilmanlaatuindeksi puuttuu")
+	}
+
+	concentrations := []struct {
+		value Value
+		name  string
+	}{
+		{a.PM25, "pienhiukkaset"},
+		{a.PM10, "hengitettävät hiukkaset"},
+		{a.NO2, "typpidioksidi"},
+		{a.O3, "otsoni"},
+	}
+	for _, concentration := range concentrations {
+		if concentration.value.Valid {
+			fmt.Fprintf(&output, ", %s %.1f µg/m³", concentration.name, concentration.value.Value)
+		}
+	}
+
+	output.WriteString(formatStationName(a.Station))
+
+	return output.String()
+}
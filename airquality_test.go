@@ -0,0 +1,112 @@
+package fmi
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestAirQualityClass(t *testing.T) {
+	var tests = []struct {
+		index float64
+		class int
+	}{
+		{math.NaN(), -1},
+		{0, -1},
+		{1, 1},
+		{1.6, 2},
+		{3.2, 3},
+		{5, 5},
+		{6.5, 5},
+	}
+	for _, test := range tests {
+		if got := airQualityClass(test.index); got != test.class {
+			t.Errorf("airQualityClass(%v) = %d; want %d", test.index, got, test.class)
+		}
+	}
+}
+
+func TestFormatAirQuality(t *testing.T) {
+	var tests = []struct {
+		a AirQuality
+		s string
+	}{
+		{
+			AirQuality{Index: Value{1.2, true}},
+			"Ilmanlaatu paikassa Helsinki: ilmanlaatu hyvä (indeksi 1)",
+		},
+		{
+			AirQuality{Station: Station{Name: "Helsinki Kallio 2"}, Index: Value{2.4, true}, PM25: Value{3.14, true}, PM10: Value{8, true}, NO2: Value{12.3, true}, O3: Value{55, true}},
+			"Ilmanlaatu paikassa Helsinki: ilmanlaatu tyydyttävä (indeksi 2), pienhiukkaset 3.1 µg/m³, hengitettävät hiukkaset 8.0 µg/m³, typpidioksidi 12.3 µg/m³, otsoni 55.0 µg/m³ (Helsinki Kallio 2)",
+		},
+		{
+			AirQuality{NO2: Value{80, true}},
+			"Ilmanlaatu paikassa Helsinki: ilmanlaatuindeksi puuttuu, typpidioksidi 80.0 µg/m³",
+		},
+	}
+	for _, test := range tests {
+		if got := formatAirQuality("helsinki", test.a); got != test.s {
+			t.Errorf("formatAirQuality(%v) = '%s'; want '%s'", test.a, got, test.s)
+		}
+	}
+}
+
+func TestClientAirQuality(t *testing.T) {
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Helsinki Kallio 2", FMISID: 100662, Latitude: 60.18739, Longitude: 24.95068, Networks: []string{"Ilmanlaatuasema"},
+			Values: map[string]float64{"AQINDEX_PT1H_avg": 1, "PM25_PT1H_avg": 2.5, "PM10_PT1H_avg": 6, "NO2_PT1H_avg": 9.1, "O3_PT1H_avg": 61}},
+	)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	a, err := c.AirQualityAt(ctx, FMISID(100662))
+	if err != nil {
+		t.Fatalf("AirQualityAt() returned error %v", err)
+	}
+	if a.Index != (Value{1, true}) || a.PM25 != (Value{2.5, true}) || a.O3 != (Value{61, true}) || a.Station.Name != "Helsinki Kallio 2" {
+		t.Errorf("AirQualityAt() = %+v; want index 1, PM2.5 2.5 and O3 61 at Helsinki Kallio 2", a)
+	}
+
+	s, err := c.AirQualitySummary(ctx, "Helsinki")
+	if err != nil {
+		t.Fatalf("AirQualitySummary('Helsinki') returned error %v", err)
+	}
+	if want := "ilmanlaatu hyvä (indeksi 1)"; !strings.Contains(s, want) {
+		t.Errorf("AirQualitySummary('Helsinki') = '%s'; should contain '%s'", s, want)
+	}
+
+	q := srv.Requests()[0]
+	if got, want := q.Get("storedquery_id"), strings.TrimSuffix(airQualityQuery, "simple")+"multipointcoverage"; got != want {
+		t.Errorf("AirQualityAt() requested stored query %s; want %s", got, want)
+	}
+}
+
+func TestClientAirQualityNewestHour(t *testing.T) {
+	newest := time.Now().UTC().Truncate(time.Hour)
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Helsinki Kallio 2", FMISID: 100662, Latitude: 60.18739, Longitude: 24.95068, Networks: []string{"Ilmanlaatuasema"},
+		// The newest hour is missing the index
+		Func: func(parameter string, ts time.Time) (float64, bool) {
+			if !ts.Before(newest) {
+				return 20, parameter == "PM10_PT1H_avg"
+			}
+			return map[string]float64{"AQINDEX_PT1H_avg": 2, "PM25_PT1H_avg": 3, "PM10_PT1H_avg": 6}[parameter], parameter != "NO2_PT1H_avg" && parameter != "O3_PT1H_avg"
+		},
+	})
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	a, err := c.AirQualityAt(context.Background(), FMISID(100662))
+	if err != nil {
+		t.Fatalf("AirQualityAt() returned error %v", err)
+	}
+	// All values are of the hour before, which has the index
+	if want := newest.Add(-time.Hour); !a.Time.Equal(want) || a.Index != (Value{2, true}) || a.PM10 != (Value{6, true}) || a.PM25 != (Value{3, true}) || a.NO2.Valid {
+		t.Errorf("AirQualityAt() = %+v; want index 2, PM10 6 and PM2.5 3 of %v", a, want)
+	}
+}
@@ -0,0 +1,164 @@
+package fmi
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency is the number of requests a batch makes at a time
+const DefaultConcurrency = 4
+
+// batchSize is the number of stations combined into one request
+const batchSize = 20
+
+// Result is the latest observation for a location of a batch, or the error
+// fetching it
+type Result struct {
+	Observation Observation
+	Err         error
+}
+
+// Batch returns the latest weather observations for many locations. FMISID
+// locations are combined into as few requests as possible, other locations
+// are fetched concurrently. Every location has a result in the returned map.
+func (c *Client) Batch(ctx context.Context, locs []Location) map[Location]Result {
+	results := make(map[Location]Result, len(locs))
+	var mu sync.Mutex
+	save := func(loc Location, r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[loc] = r
+	}
+
+	seen := make(map[Location]bool, len(locs))
+	stations := make([]Location, 0)
+	others := make([]Location, 0)
+	for _, loc := range locs {
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+		if loc.param == "fmisid" {
+			stations = append(stations, loc)
+		} else {
+			others = append(others, loc)
+		}
+	}
+
+	jobs := make([]func(), 0)
+	for i := 0; i < len(stations); i += batchSize {
+		chunk := stations[i:min(i+batchSize, len(stations))]
+		jobs = append(jobs, func() {
+			for loc, r := range c.batchStations(ctx, chunk) {
+				save(loc, r)
+			}
+		})
+	}
+	for _, loc := range others {
+		jobs = append(jobs, func() {
+			obs, err := c.ObservationsAt(ctx, loc)
+			save(loc, Result{obs, err})
+		})
+	}
+
+	concurrency := c.concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			job()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// BatchWeather returns the latest weather observations for many places,
+// see Batch
+func (c *Client) BatchWeather(ctx context.Context, places []string) map[string]Result {
+	locs := make([]Location, 0, len(places))
+	for _, place := range places {
+		locs = append(locs, Place(place))
+	}
+
+	results := make(map[string]Result, len(places))
+	for loc, r := range c.Batch(ctx, locs) {
+		results[loc.value] = r
+	}
+	return results
+}
+
+// batchStations fetches the latest observations of FMISID locations in one
+// request, and with WithPressureTendency the pressures for their
+// tendencies in another
+func (c *Client) batchStations(ctx context.Context, locs []Location) map[Location]Result {
+	results := make(map[Location]Result, len(locs))
+	fail := func(err error) map[Location]Result {
+		for _, loc := range locs {
+			results[loc] = Result{Err: err}
+		}
+		return results
+	}
+
+	start, end := latestWindow()
+	q := observationQuery(locs[0], measures, start, end, 10*time.Minute)
+	for _, loc := range locs[1:] {
+		q.Add(loc.param, loc.value)
+	}
+	// Unlike the simple format, these formats name the station of each
+	// row, so the rows are matched to the locations by FMISID
+	format := MultiPointCoverageFormat
+	if c.format == TimeValuePairFormat {
+		format = TimeValuePairFormat
+	}
+	q.Set("storedquery_id", "fmi::observations::weather::"+string(format))
+
+	collection, err := c.fetchFeatures(ctx, q)
+	if err != nil {
+		return fail(err)
+	}
+	if collection.Matched == 0 || collection.Returned == 0 {
+		return fail(ErrNoData)
+	}
+	c.newQualityChecker().checkCollection(&collection)
+
+	rows := make(map[int]row, len(locs))
+	pressures := false
+	for _, r := range extractStationObservations(collection, measures) {
+		if s, ok := collection.Stations[r.Location]; ok && s.FMISID != 0 {
+			rows[s.FMISID] = r
+			pressures = pressures || !math.IsNaN(r.Values["p_sea"])
+		}
+	}
+
+	// The pressure tendencies are auxiliary, so failures leave them out
+	var history map[time.Time]map[string]observations
+	if c.pressureTendency && pressures {
+		history, _ = c.fetchPressureHistory(ctx, q, start, end)
+	}
+
+	for _, loc := range locs {
+		id, _ := strconv.Atoi(loc.value)
+		r, ok := rows[id]
+		if !ok {
+			results[loc] = Result{Err: ErrNoData}
+			continue
+		}
+		addPressureTendency(r, history)
+		obs := newObservation(r)
+		obs.Station = collection.Stations[r.Location]
+		results[loc] = Result{Observation: obs}
+	}
+	return results
+}
@@ -0,0 +1,112 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestBatch(t *testing.T) {
+	now := time.Now()
+	stations := append(testStations[:2:2], fmitest.Station{
+		Name: "Turku Artukainen", FMISID: 100949, Latitude: 60.45, Longitude: 22.27,
+		Func: func(parameter string, ts time.Time) (float64, bool) {
+			switch parameter {
+			case "t2m":
+				return 12, true
+			case "p_sea":
+				// rises 1 hPa an hour
+				return 1000 + ts.Sub(now).Hours(), true
+			}
+			return 0, false
+		},
+	}, testStations[2])
+	srv := fmitest.NewServer(stations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithConcurrency(2), WithPressureTendency())
+
+	results := c.Batch(context.Background(), []Location{
+		FMISID(100971), FMISID(100968), FMISID(100949), FMISID(100971), FMISID(1), Place("Helsinki"), Place("Narnia"),
+	})
+	if len(results) != 6 {
+		t.Errorf("Batch() returned %d results; want 6", len(results))
+	}
+
+	var tests = []struct {
+		loc         Location
+		name        string
+		temperature float64
+		err         error
+	}{
+		{FMISID(100971), "Helsinki Kaisaniemi", 18.5, nil},
+		{FMISID(100968), "Helsinki-Vantaa lentoasema", 17.9, nil},
+		{FMISID(100949), "Turku Artukainen", 12, nil},
+		{FMISID(1), "", 0, ErrNoData},
+		{Place("Helsinki"), "Helsinki Kaisaniemi", 18.5, nil},
+		{Place("Narnia"), "", 0, ErrPlaceNotFound},
+	}
+	for _, test := range tests {
+		r, ok := results[test.loc]
+		if !ok {
+			t.Errorf("Batch() has no result for %s", test.loc)
+			continue
+		}
+		if test.err != nil {
+			if !errors.Is(r.Err, test.err) {
+				t.Errorf("Batch()[%s] returned error %v; want %v", test.loc, r.Err, test.err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Observation.Station.Name != test.name || r.Observation.Temperature.Value != test.temperature {
+			t.Errorf("Batch()[%s] = %s %v, %v; want %s %.1f", test.loc, r.Observation.Station.Name, r.Observation.Temperature, r.Err, test.name, test.temperature)
+		}
+	}
+
+	if tendency := results[FMISID(100949)].Observation.PressureTendency; !tendency.Valid || tendency.Value != 3 {
+		t.Errorf("Batch()[fmisid 100949] pressure tendency = %v; want 3", tendency)
+	}
+
+	// observations, the pressures three hours earlier, Helsinki and Narnia
+	if n := len(srv.Requests()); n != 4 {
+		t.Errorf("Batch() made %d requests; want 4", n)
+	}
+}
+
+func TestBatchColocatedStations(t *testing.T) {
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Kumpula", FMISID: 101004, Latitude: 60.20307, Longitude: 24.96131, Values: map[string]float64{"t2m": 10}},
+		fmitest.Station{Name: "Kumpula mast", FMISID: 101005, Latitude: 60.20310, Longitude: 24.96140, Values: map[string]float64{"t2m": 8}},
+	)
+	defer srv.Close()
+
+	for _, format := range []ResponseFormat{AutoFormat, TimeValuePairFormat} {
+		c := NewClient(WithBaseURL(srv.URL), WithResponseFormat(format))
+		results := c.Batch(context.Background(), []Location{FMISID(101005), FMISID(101004)})
+		if r := results[FMISID(101004)]; r.Err != nil || r.Observation.Station.Name != "Kumpula" || r.Observation.Temperature.Value != 10 {
+			t.Errorf("%s: Batch()[fmisid 101004] = %s %v, %v; want Kumpula 10", format, r.Observation.Station.Name, r.Observation.Temperature, r.Err)
+		}
+		if r := results[FMISID(101005)]; r.Err != nil || r.Observation.Station.Name != "Kumpula mast" || r.Observation.Temperature.Value != 8 {
+			t.Errorf("%s: Batch()[fmisid 101005] = %s %v, %v; want Kumpula mast 8", format, r.Observation.Station.Name, r.Observation.Temperature, r.Err)
+		}
+	}
+}
+
+func TestBatchWeather(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	results := c.BatchWeather(context.Background(), []string{"Helsinki", "Pihtipudas", "Narnia"})
+	if r := results["Helsinki"]; r.Err != nil || r.Observation.Temperature.Value != 18.5 {
+		t.Errorf("BatchWeather()['Helsinki'] = %v, %v; want 18.5", r.Observation.Temperature, r.Err)
+	}
+	if r := results["Pihtipudas"]; r.Err != nil || !r.Observation.Temperature.Valid {
+		t.Errorf("BatchWeather()['Pihtipudas'] = %v, %v; want a temperature", r.Observation.Temperature, r.Err)
+	}
+	if r := results["Narnia"]; !errors.Is(r.Err, ErrPlaceNotFound) {
+		t.Errorf("BatchWeather()['Narnia'] returned error %v; want ErrPlaceNotFound", r.Err)
+	}
+}
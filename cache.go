@@ -0,0 +1,136 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cacheInterval is the cadence FMI updates observations at. Cached
+// responses expire at the next multiple of it.
+const cacheInterval = 10 * time.Minute
+
+// Cache stores responses of FMI's API keyed by request URL. A Cache must be
+// safe for concurrent use. See WithCache.
+type Cache interface {
+	// Get returns the value of a key, or false if there is none or it
+	// has expired
+	Get(key string) ([]byte, bool)
+	// Set stores the value of a key until expires
+	Set(key string, value []byte, expires time.Time)
+}
+
+// MemoryCache is a Cache keeping responses in memory. Create memory caches
+// with NewMemoryCache.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the value of a key, or false if there is none or it has
+// expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores the value of a key until expires. Expired entries are removed
+// at the same time.
+func (m *MemoryCache) Set(key string, value []byte, expires time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, e := range m.entries {
+		if !now.Before(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = cacheEntry{value, expires}
+}
+
+// cacheExpiry returns when a response fetched at t expires, i.e. the start
+// of the next observation interval
+func cacheExpiry(t time.Time) time.Time {
+	return t.Truncate(cacheInterval).Add(cacheInterval)
+}
+
+// flight is a request in progress
+type flight struct {
+	done    chan struct{}
+	value   []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightGroup deduplicates concurrent requests with the same key
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// do calls fn, or if a call with the same key is in progress, waits for it
+// and returns its result. The call is shared, so it runs on a context none
+// of the callers can cancel, and is cancelled only when every caller has
+// stopped waiting. Each caller stops waiting when its own ctx is done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+
+		go func() {
+			f.value, f.err = fn(callCtx)
+			cancel()
+
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if f.waiters--; f.waiters == 0 {
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, ctx.Err())
+	}
+}
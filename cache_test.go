@@ -0,0 +1,152 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("a", []byte("1"), time.Now().Add(time.Minute))
+	cache.Set("b", []byte("2"), time.Now().Add(-time.Second))
+
+	if v, ok := cache.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("Get('a') = %s, %t; want 1", v, ok)
+	}
+	if v, ok := cache.Get("b"); ok {
+		t.Errorf("Get('b') = %s; want expired", v)
+	}
+	if v, ok := cache.Get("c"); ok {
+		t.Errorf("Get('c') = %s; want not found", v)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	var tests = []struct {
+		t, expires time.Time
+	}{
+		{time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 1, 12, 9, 59, 0, time.UTC), time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 1, 23, 55, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		if got := cacheExpiry(test.t); !got.Equal(test.expires) {
+			t.Errorf("cacheExpiry(%v) = %v; want %v", test.t, got, test.expires)
+		}
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithCache(NewMemoryCache()))
+
+	// Fixed time ranges keep the request URLs equal across the 10 minute
+	// boundaries of the latest observations
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	series := func(place string) error {
+		_, err := c.TimeSeries(context.Background(), Place(place), start, end, 10*time.Minute)
+		return err
+	}
+
+	for range 3 {
+		if err := series("Helsinki"); err != nil {
+			t.Fatalf("TimeSeries('Helsinki') returned error %v", err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("cached TimeSeries() made %d requests; want 1", n)
+	}
+
+	srv.FailNext(1, 503, "ServiceUnavailable")
+	if err := series("Pihtipudas"); err == nil {
+		t.Errorf("TimeSeries('Pihtipudas') should fail")
+	}
+	if err := series("Pihtipudas"); err != nil {
+		t.Errorf("TimeSeries('Pihtipudas') should not cache failures, instead got error %v", err)
+	}
+
+	srv.SetDelay(50 * time.Millisecond)
+	n := len(srv.Requests())
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := series("Viitasaari"); err != nil {
+				t.Errorf("TimeSeries('Viitasaari') returned error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(srv.Requests()) - n; got != 1 {
+		t.Errorf("concurrent TimeSeries() made %d requests; want 1", got)
+	}
+}
+
+func TestFlightGroupCancel(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	fn := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		select {
+		case <-release:
+			return []byte("ok"), nil
+		case <-ctx.Done():
+			close(cancelled)
+			return nil, ctx.Err()
+		}
+	}
+
+	// The caller starting the call gives up, the other one still gets
+	// the result
+	leader, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := g.do(leader, "a", fn)
+		leaderErr <- err
+	}()
+	<-started
+	result := make(chan string)
+	go func() {
+		v, err := g.do(context.Background(), "a", fn)
+		if err != nil {
+			t.Errorf("do() returned error %v", err)
+		}
+		result <- string(v)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("do() for a cancelled caller returned error %v; want context.Canceled", err)
+	}
+	close(release)
+	if v := <-result; v != "ok" {
+		t.Errorf("do() = '%s'; want 'ok'", v)
+	}
+
+	// The call is cancelled once nobody waits for it
+	started = make(chan struct{})
+	release = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if _, err := g.do(ctx, "b", fn); !errors.Is(err, context.Canceled) {
+		t.Errorf("do() for a cancelled caller returned error %v; want context.Canceled", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("do() did not cancel a call nobody waits for")
+	}
+}
@@ -0,0 +1,251 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the address of FMI's open data WFS service
+const DefaultBaseURL = "http://opendata.fmi.fi/wfs"
+
+// DefaultWarningsURL is the address of FMI's CAP warnings feed
+const DefaultWarningsURL = "https://alerts.fmi.fi/cap/feed/atom_fi-FI.xml"
+
+// DefaultTimeout is the time limit for a single request to FMI's API
+const DefaultTimeout = 10 * time.Second
+
+// Client fetches data from FMI's open API. Create clients with NewClient,
+// a Client is safe for concurrent use.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	warningsURL string
+	timeout     time.Duration
+	userAgent   string
+
+	// mergeDistance is the distance in km within which missing values
+	// are filled from other stations, 0 disables merging
+	mergeDistance float64
+
+	// pressureTendency enables fetching the pressure three hours earlier
+	// for the pressure tendency
+	pressureTendency bool
+
+	// concurrency is the number of requests a batch makes at a time
+	concurrency int
+
+	format ResponseFormat
+
+	// quality holds the limits of quality control, nil disables it
+	quality *QualityLimits
+
+	retries    int
+	retryDelay time.Duration
+	limiter    *limiter
+
+	// rate, burst and dailyLimit configure the limiter
+	rate       float64
+	burst      int
+	dailyLimit int
+
+	cache   Cache
+	flights flightGroup
+
+	stationsMu sync.Mutex
+	stations   *Catalog
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. to share
+// a connection pool
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithBaseURL sets the address of the WFS endpoint, e.g. a mirror or
+// a local fake server
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithWarningsURL sets the address of the CAP warnings feed
+func WithWarningsURL(u string) Option {
+	return func(c *Client) {
+		c.warningsURL = u
+	}
+}
+
+// WithTimeout sets the time limit for a single request. Zero disables the
+// limit and leaves cancellation to the caller's context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with requests
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithMergeDistance fills values the nearest station did not report from
+// other stations within distance km. The stations the values came from are
+// recorded in Observation.Sources and mentioned in written descriptions.
+func WithMergeDistance(distance float64) Option {
+	return func(c *Client) {
+		c.mergeDistance = distance
+	}
+}
+
+// WithPressureTendency computes the three hour pressure tendency of the
+// latest observations. It takes another request for the pressure three
+// hours earlier, so it is off by default. Time series compute the tendency
+// from their own observations regardless.
+func WithPressureTendency() Option {
+	return func(c *Client) {
+		c.pressureTendency = true
+	}
+}
+
+// WithCache caches responses in cache until FMI's next 10 minute
+// observation update. Concurrent identical requests are made only once.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithConcurrency sets the number of requests a batch makes at a time,
+// DefaultConcurrency by default
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// NewClient returns a Client configured with opts
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient:  http.DefaultClient,
+		baseURL:     DefaultBaseURL,
+		warningsURL: DefaultWarningsURL,
+		timeout:     DefaultTimeout,
+		concurrency: DefaultConcurrency,
+		quality:     DefaultQualityLimits(),
+		dailyLimit:  DefaultDailyLimit,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.rate > 0 {
+		c.limiter = newLimiter(time.Now(), c.rate, c.burst, c.dailyLimit)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	return c
+}
+
+// defaultClient is used by the package level functions
+var defaultClient = NewClient()
+
+// get does a HTTP GET request against FMI's API with query q and returns
+// the response body. Responses other than 200 are returned as *APIError.
+func (c *Client) get(ctx context.Context, q url.Values) ([]byte, error) {
+	endpoint, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.RawQuery = q.Encode()
+
+	return c.fetch(ctx, endpoint.String())
+}
+
+// fetch does a HTTP GET request against an address and returns the
+// response body, using the cache if there is one. Responses other than 200
+// are returned as *APIError.
+func (c *Client) fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	if c.cache == nil {
+		return c.retry(ctx, endpoint)
+	}
+	if body, ok := c.cache.Get(endpoint); ok {
+		return body, nil
+	}
+	// Each attempt of the shared call is still limited by the client's
+	// timeout in request
+	return c.flights.do(ctx, endpoint, func(ctx context.Context) ([]byte, error) {
+		if body, ok := c.cache.Get(endpoint); ok {
+			return body, nil
+		}
+		body, err := c.retry(ctx, endpoint)
+		if err == nil {
+			c.cache.Set(endpoint, body, cacheExpiry(time.Now()))
+		}
+		return body, err
+	})
+}
+
+// request does a HTTP GET request against an address and returns the
+// response body
+func (c *Client) request(ctx context.Context, endpoint string) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	resp, err := c.open(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return body, nil
+}
+
+// open does a HTTP GET request against an address and returns the
+// response for reading its body. Responses other than 200 are returned as
+// *APIError.
+func (c *Client) open(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return nil, newAPIError(resp.StatusCode, resp.Header, body)
+	}
+
+	return resp, nil
+}
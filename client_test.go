@@ -0,0 +1,73 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCollection = `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="2024-01-01T12:00:00Z" numberMatched="2" numberReturned="2"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2"
+  xmlns:BsWfs="http://xml.fmi.fi/schema/wfs/2.0">
+  <wfs:member>
+    <BsWfs:BsWfsElement gml:id="BsWfsElement.1.1.1">
+      <BsWfs:Location><gml:Point gml:id="BsWfsElementP.1.1.1"><gml:pos>60.17523 24.94459 </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>2024-01-01T12:00:00Z</BsWfs:Time>
+      <BsWfs:ParameterName>t2m</BsWfs:ParameterName>
+      <BsWfs:ParameterValue>-2.5</BsWfs:ParameterValue>
+    </BsWfs:BsWfsElement>
+  </wfs:member>
+  <wfs:member>
+    <BsWfs:BsWfsElement gml:id="BsWfsElement.1.1.2">
+      <BsWfs:Location><gml:Point gml:id="BsWfsElementP.1.1.2"><gml:pos>60.17523 24.94459 </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>2024-01-01T12:00:00Z</BsWfs:Time>
+      <BsWfs:ParameterName>rh</BsWfs:ParameterName>
+      <BsWfs:ParameterValue>80</BsWfs:ParameterValue>
+    </BsWfs:BsWfsElement>
+  </wfs:member>
+</wfs:FeatureCollection>`
+
+func TestClientOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "fmi-test" {
+			t.Errorf("User-Agent = '%s'; want 'fmi-test'", ua)
+		}
+		if place := r.URL.Query().Get("place"); place != "Helsinki" {
+			t.Errorf("place = '%s'; want 'Helsinki'", place)
+		}
+		w.Write([]byte(testCollection))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithUserAgent("fmi-test"))
+	s, err := c.Weather(context.Background(), "Helsinki")
+	if err != nil || !strings.Contains(s, "lämpötila -2.5°C") {
+		t.Errorf("Weather('Helsinki') = '%s', %v; want temperature -2.5°C", s, err)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithTimeout(10*time.Millisecond))
+	if s, err := c.Weather(context.Background(), "Helsinki"); err == nil {
+		t.Errorf("Weather('Helsinki') should time out, instead got '%s'", s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = NewClient(WithBaseURL(srv.URL), WithTimeout(0))
+	if s, err := c.Weather(ctx, "Helsinki"); err == nil {
+		t.Errorf("Weather('Helsinki') with a cancelled context should fail, instead got '%s'", s)
+	}
+}
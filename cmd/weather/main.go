@@ -1,25 +1,171 @@
 package main
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"time"
+	// Embedded so that Finnish time works without the system's time zone
+	// database, e.g. in scratch and distroless images
+	_ "time/tzdata"
 
 	"github.com/kari/fmi"
+	"golang.org/x/text/language"
 )
 
 var Version = "development"
 
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1 // fetching or printing the weather failed
+	exitUsage = 2 // invalid arguments
+)
+
+// output is the JSON representation of an observation at a place
+type output struct {
+	Place string `json:"place"`
+	fmi.Observation
+}
+
+// column is a CSV column and the value of an observation in it
+type column struct {
+	name  string
+	value func(o fmi.Observation) fmi.Value
+}
+
+var columns = []column{
+	{"temperature", func(o fmi.Observation) fmi.Value { return o.Temperature }},
+	{"wind_speed", func(o fmi.Observation) fmi.Value { return o.WindSpeed }},
+	{"wind_gust", func(o fmi.Observation) fmi.Value { return o.WindGust }},
+	{"wind_direction", func(o fmi.Observation) fmi.Value { return o.WindDirection }},
+	{"humidity", func(o fmi.Observation) fmi.Value { return o.Humidity }},
+	{"dew_point", func(o fmi.Observation) fmi.Value { return o.DewPoint }},
+	{"precipitation", func(o fmi.Observation) fmi.Value { return o.Precipitation }},
+	{"precipitation_intensity", func(o fmi.Observation) fmi.Value { return o.PrecipitationIntensity }},
+	{"snow_depth", func(o fmi.Observation) fmi.Value { return o.SnowDepth }},
+	{"cloud_cover", func(o fmi.Observation) fmi.Value { return o.CloudCover }},
+	{"radiation", func(o fmi.Observation) fmi.Value { return o.Radiation }},
+	{"present_weather", func(o fmi.Observation) fmi.Value { return o.PresentWeather }},
+	{"pressure", func(o fmi.Observation) fmi.Value { return o.Pressure }},
+	{"pressure_tendency", func(o fmi.Observation) fmi.Value { return o.PressureTendency }},
+	{"visibility", func(o fmi.Observation) fmi.Value { return o.Visibility }},
+}
+
 func main() {
-	if len(os.Args) <= 1 {
-		fmt.Println("Usage:", os.Args[0], "<paikka>")
-	} else if os.Args[1] == "version" {
-		fmt.Println("Version:", Version)
-	} else {
-		weather, err := fmi.Weather(os.Args[1])
-		if err != nil {
-			fmt.Println(err.Error())
+	os.Exit(run(fmi.NewClient(), os.Args, os.Stdout, os.Stderr))
+}
+
+// run runs the command with arguments args and returns the exit code
+func run(c *fmi.Client, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "tulostusmuoto: text, json tai csv")
+	lang := flags.String("lang", "fi", "tekstimuotoisen tulosteen kieli: fi, sv tai en")
+	header := flags.Bool("header", true, "CSV-otsikkorivi, false lisättäessä olemassa olevaan tiedostoon")
+	timeout := flags.Duration("timeout", fmi.DefaultTimeout, "aikakatkaisu")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage:", args[0], "[-format=text|json|csv] [-header=false] [-lang=fi|sv|en] <paikka>")
+		fmt.Fprintln(stderr, "      ", args[0], "version")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if flags.Arg(0) == "version" {
+		fmt.Fprintln(stdout, "Version:", Version)
+		return exitOK
+	}
+
+	tag, err := language.Parse(*lang)
+	if err != nil {
+		fmt.Fprintf(stderr, "tuntematon kieli %q\n", *lang)
+		return exitUsage
+	}
+	var write func(io.Writer, string, fmi.Observation) error
+	switch *format {
+	case "text":
+		write = func(w io.Writer, place string, o fmi.Observation) error {
+			_, err := fmt.Fprintln(w, fmi.FormatObservation(place, o, tag))
+			return err
+		}
+	case "json":
+		write = writeJSON
+	case "csv":
+		write = func(w io.Writer, place string, o fmi.Observation) error {
+			return writeCSV(w, place, o, *header)
+		}
+	default:
+		fmt.Fprintf(stderr, "tuntematon tulostusmuoto %q\n", *format)
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	place := flags.Arg(0)
+	obs, err := c.Observations(ctx, place)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if err := write(stdout, place, obs); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func writeJSON(w io.Writer, place string, o fmi.Observation) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output{Place: place, Observation: o})
+}
+
+// writeCSV writes a row of the observation, preceded by a header if header
+// is true. Missing values are left empty.
+func writeCSV(w io.Writer, place string, o fmi.Observation, header bool) error {
+	names := []string{"place", "station", "fmisid", "latitude", "longitude", "time"}
+	record := []string{
+		place,
+		o.Station.Name,
+		"",
+		strconv.FormatFloat(o.Station.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(o.Station.Longitude, 'f', -1, 64),
+		o.Time.Format(time.RFC3339),
+	}
+	if o.Station.FMISID != 0 {
+		record[2] = strconv.Itoa(o.Station.FMISID)
+	}
+	for _, col := range columns {
+		names = append(names, col.name)
+		if v := col.value(o); v.Valid {
+			record = append(record, strconv.FormatFloat(v.Value, 'f', -1, 64))
 		} else {
-			fmt.Println(weather)
+			record = append(record, "")
 		}
 	}
+
+	cw := csv.NewWriter(w)
+	if header {
+		cw.Write(names)
+	}
+	cw.Write(record)
+	cw.Flush()
+	return cw.Error()
 }
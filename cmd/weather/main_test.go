@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kari/fmi"
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestRun(t *testing.T) {
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Turku Artukainen", FMISID: 100949, Latitude: 60.45, Longitude: 22.27,
+		Values: map[string]float64{"t2m": 18.5, "ws_10min": 4, "wd_10min": 270, "rh": 56},
+	})
+	defer srv.Close()
+	c := fmi.NewClient(fmi.WithBaseURL(srv.URL))
+
+	var tests = []struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{[]string{"weather"}, exitUsage, "", "Usage:"},
+		{[]string{"weather", "version"}, exitOK, "Version: development", ""},
+		{[]string{"weather", "-format=xml", "Turku"}, exitUsage, "", "tulostusmuoto"},
+		{[]string{"weather", "Turku"}, exitOK, "Turku: lämpötila 18.5°C", ""},
+		{[]string{"weather", "-lang=en", "Turku"}, exitOK, "Turku: temperature 18.5°C", ""},
+		{[]string{"weather", "Narnia"}, exitError, "", "ei löytynyt"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(c, test.args, &stdout, &stderr)
+		if code != test.code || !strings.Contains(stdout.String(), test.stdout) || !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("run(%v) = %d, stdout '%s', stderr '%s'; want %d, '%s', '%s'", test.args, code, stdout.String(), stderr.String(), test.code, test.stdout, test.stderr)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(c, []string{"weather", "-format=json", "Turku"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(-format=json) = %d, %s", code, stderr.String())
+	}
+	var out struct {
+		Place       string
+		Station     fmi.Station
+		Temperature fmi.Value
+		WindGust    fmi.Value `json:"wind_gust"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("run(-format=json) printed invalid JSON: %v", err)
+	}
+	if out.Place != "Turku" || out.Station.Latitude != 60.45 || out.Temperature.Value != 18.5 || out.WindGust.Valid {
+		t.Errorf("run(-format=json) = %+v", out)
+	}
+
+	stdout.Reset()
+	if code := run(c, []string{"weather", "-format=csv", "Turku"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(-format=csv) = %d, %s", code, stderr.String())
+	}
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("run(-format=csv) printed %v, %v; want a header and a row", records, err)
+	}
+	if records[0][6] != "temperature" || records[1][6] != "18.5" || records[1][8] != "" {
+		t.Errorf("run(-format=csv) = %v", records)
+	}
+
+	// Appending to a file leaves the header out
+	stdout.Reset()
+	if code := run(c, []string{"weather", "-format=csv", "-header=false", "Turku"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(-format=csv -header=false) = %d, %s", code, stderr.String())
+	}
+	records, err = csv.NewReader(&stdout).ReadAll()
+	if err != nil || len(records) != 1 || records[0][0] != "Turku" || records[0][6] != "18.5" {
+		t.Errorf("run(-format=csv -header=false) printed %v, %v; want a single row", records, err)
+	}
+}
@@ -0,0 +1,121 @@
+package fmi
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoPlace is returned when no place was given
+	ErrNoPlace = errors.New("paikkaa ei syötetty")
+	// ErrPlaceNotFound is returned when FMI does not recognise the place
+	ErrPlaceNotFound = errors.New("säähavaintopaikkaa ei löytynyt")
+	// ErrNoData is returned when the query matched no observations
+	ErrNoData = errors.New("säähavaintoja ei löytynyt")
+	// ErrUnavailable is returned when FMI's API could not be reached or
+	// it failed to respond
+	ErrUnavailable = errors.New("säähavaintoja ei saatu haettua")
+	// ErrParse is returned when the response could not be parsed
+	ErrParse = errors.New("virhe parsittaessa havaintoja")
+	// ErrBadRequest is returned when FMI's API rejected the query
+	ErrBadRequest = errors.New("virheellinen pyyntö")
+)
+
+// APIError is returned when FMI's API responds with a status other than
+// 200. Code, Locator and Texts are parsed from the WFS ExceptionReport in
+// the response body, if there was one.
+type APIError struct {
+	StatusCode int
+	Code       string // e.g. OperationParsingFailed
+	Locator    string
+	Texts      []string
+	RetryAfter time.Duration // from the Retry-After header, 0 if none
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s (HTTP %d", e.Unwrap(), e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if len(e.Texts) > 0 {
+		msg += ": " + e.Texts[0]
+	}
+	return msg + ")"
+}
+
+// Unwrap classifies the error as one of the package's sentinel errors
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest && e.Code == "OperationParsingFailed" && e.locationFailed():
+		// If place parsing fails, returns 400 with OperationParsingFailed
+		return ErrPlaceNotFound
+	case e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return ErrBadRequest
+}
+
+// locationParams are the query parameters selecting a location
+var locationParams = []string{"place", "geoid", "fmisid", "wmo", "latlon"}
+
+// locationFailed reports whether the exception is about the location of
+// the query rather than another parameter, e.g. bbox or starttime. FMI
+// gives unknown places as the locator, so the texts are checked too.
+func (e *APIError) locationFailed() bool {
+	if slices.Contains(locationParams, e.Locator) {
+		return true
+	}
+	for _, text := range e.Texts {
+		if strings.Contains(strings.ToLower(text), "location") {
+			return true
+		}
+	}
+	return false
+}
+
+// exceptionReport is the error document returned by FMI's WFS service
+type exceptionReport struct {
+	Exceptions []struct {
+		Code    string   `xml:"exceptionCode,attr"`
+		Locator string   `xml:"locator,attr"`
+		Texts   []string `xml:"ExceptionText"`
+	} `xml:"Exception"`
+}
+
+// newAPIError creates an APIError from a response, parsing the
+// ExceptionReport in body if possible
+func newAPIError(status int, header http.Header, body []byte) *APIError {
+	e := &APIError{StatusCode: status, RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now())}
+
+	var report exceptionReport
+	if err := xml.Unmarshal(body, &report); err == nil && len(report.Exceptions) > 0 {
+		e.Code = report.Exceptions[0].Code
+		e.Locator = report.Exceptions[0].Locator
+		for _, exception := range report.Exceptions {
+			e.Texts = append(e.Texts, exception.Texts...)
+		}
+	}
+
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as a
+// HTTP date relative to now, returning 0 if it is missing or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
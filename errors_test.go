@@ -0,0 +1,106 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testExceptionReport = `<?xml version="1.0" encoding="UTF-8"?>
+<ExceptionReport xmlns="http://www.opengis.net/ows/1.1" version="2.0.0">
+  <Exception exceptionCode="OperationParsingFailed" locator="narnia">
+    <ExceptionText>Invalid parameter value!</ExceptionText>
+    <ExceptionText>No locations found for the place with the requested language!</ExceptionText>
+  </Exception>
+</ExceptionReport>`
+
+const testEmptyCollection = `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="2024-01-01T12:00:00Z" numberMatched="0" numberReturned="0"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0"></wfs:FeatureCollection>`
+
+func TestNewAPIError(t *testing.T) {
+	e := newAPIError(http.StatusBadRequest, nil, []byte(testExceptionReport))
+	if e.Code != "OperationParsingFailed" || e.Locator != "narnia" || len(e.Texts) != 2 {
+		t.Errorf("newAPIError() = %+v; want OperationParsingFailed at narnia with 2 texts", e)
+	}
+
+	var tests = []struct {
+		err    *APIError
+		target error
+	}{
+		{e, ErrPlaceNotFound},
+		{&APIError{StatusCode: http.StatusBadRequest}, ErrBadRequest},
+		{&APIError{StatusCode: http.StatusBadRequest, Code: "OperationParsingFailed", Locator: "latlon", Texts: []string{"Invalid parameter value!"}}, ErrPlaceNotFound},
+		{&APIError{StatusCode: http.StatusBadRequest, Code: "OperationParsingFailed", Locator: "bbox", Texts: []string{"Invalid parameter value!"}}, ErrBadRequest},
+		{&APIError{StatusCode: http.StatusBadRequest, Code: "OperationParsingFailed", Locator: "starttime", Texts: []string{"Invalid time interval!"}}, ErrBadRequest},
+		{&APIError{StatusCode: http.StatusTooManyRequests}, ErrUnavailable},
+		{&APIError{StatusCode: http.StatusBadGateway}, ErrUnavailable},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.err, test.target) {
+			t.Errorf("errors.Is(%v, %v) = false; want true", test.err, test.target)
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	var tests = []struct {
+		status int
+		body   string
+		target error
+	}{
+		{http.StatusBadRequest, testExceptionReport, ErrPlaceNotFound},
+		{http.StatusServiceUnavailable, "", ErrUnavailable},
+		{http.StatusOK, "<wfs:FeatureCollection", ErrParse},
+		{http.StatusOK, testEmptyCollection, ErrNoData},
+	}
+	for _, test := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+
+		c := NewClient(WithBaseURL(srv.URL))
+		_, err := c.Observations(context.Background(), "Narnia")
+		if !errors.Is(err, test.target) {
+			t.Errorf("Observations() with HTTP %d = %v; want %v", test.status, err, test.target)
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) != (test.status != http.StatusOK) {
+			t.Errorf("Observations() with HTTP %d = %v; APIError expected only for non-200", test.status, err)
+		}
+		srv.Close()
+	}
+
+	c := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	if _, err := c.Observations(context.Background(), "Helsinki"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Observations() against an unreachable server = %v; want %v", err, ErrUnavailable)
+	}
+	if _, err := c.Observations(context.Background(), ""); !errors.Is(err, ErrNoPlace) {
+		t.Errorf("Observations('') = %v; want %v", err, ErrNoPlace)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		value string
+		d     time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, test := range tests {
+		if got := parseRetryAfter(test.value, now); got != test.d {
+			t.Errorf("parseRetryAfter('%s') = %v; want %v", test.value, got, test.d)
+		}
+	}
+}
@@ -5,15 +5,17 @@ package fmi
 import (
 	"context"
 	"encoding/xml"
-	"errors"
-	"io"
+	"fmt"
+	"maps"
 	"math"
-	"net/http"
 	"net/url"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/text/language"
 )
 
 // simpleFeatureCollection is a struct in returned XML
@@ -22,6 +24,14 @@ type simpleFeatureCollection struct {
 	Returned  int           `xml:"numberReturned,attr"`
 	Matched   int           `xml:"numberMatched,attr"`
 	Elements  []observation `xml:"member>BsWfsElement"`
+
+	// Stations holds the stations of formats that describe them, keyed by
+	// location
+	Stations map[string]Station `xml:"-"`
+
+	// Quality holds the flags of values rejected by quality control,
+	// keyed by location and time
+	Quality map[string]map[time.Time]map[string]QualityFlag `xml:"-"`
 }
 
 // observation is a struct in returned XML
@@ -35,150 +45,412 @@ type observation struct {
 // observations holds observations for a place as a map
 type observations map[string]float64
 
+// row holds the observations of a station at one time
+type row struct {
+	Location string
+	Time     time.Time
+	Values   observations
+	Sources  map[string]Station     // stations of values merged from other rows
+	Quality  map[string]QualityFlag // flags of values rejected by quality control
+}
+
 // Weather returns current weather for a place as a written description
+// using the default client
 func Weather(place string) (string, error) {
+	return defaultClient.Weather(context.Background(), place)
+}
+
+// Weather returns current weather for a place as a written description
+func (c *Client) Weather(ctx context.Context, place string) (string, error) {
+	return c.WeatherIn(ctx, place, language.Finnish)
+}
+
+// WeatherIn returns current weather for a place as a written description in
+// the supported language best matching tag: Finnish, Swedish or English
+func (c *Client) WeatherIn(ctx context.Context, place string, tag language.Tag) (string, error) {
 
 	if place == "" {
-		return "", errors.New("paikkaa ei syötetty")
+		return "", ErrNoPlace
 	}
 
-	obs, err := getObservations(place)
+	latest, _, err := c.getObservations(ctx, Place(place))
 	if err != nil {
 		return "", err
 	}
 
-	weather := formatObservations(place, obs)
+	weather := localeFor(tag).formatObservations(place, latest.Values, sourceNames(latest.Sources))
 
 	return weather, nil
 }
 
+// Observations returns the latest weather observations for a place
+func (c *Client) Observations(ctx context.Context, place string) (Observation, error) {
+	return c.ObservationsAt(ctx, Place(place))
+}
+
+// ObservationsAt returns the latest weather observations for a location.
+// When the location matches several stations, the one with the most
+// measurements is chosen.
+func (c *Client) ObservationsAt(ctx context.Context, loc Location) (Observation, error) {
+	latest, stations, err := c.getObservations(ctx, loc)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	obs := newObservation(latest)
+	obs.Station = loc.station(c.stationOf(stations, latest.Location, WeatherStation))
+
+	return obs, nil
+}
+
+// LatestObservations returns the latest weather observations of every
+// station matching a location, e.g. all stations inside a BBox
+func (c *Client) LatestObservations(ctx context.Context, loc Location) ([]Observation, error) {
+	collection, err := c.fetchLatest(ctx, loc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := extractStationObservations(collection, measures)
+	if len(rows) == 0 {
+		return nil, ErrNoData
+	}
+
+	obs := make([]Observation, 0, len(rows))
+	for _, r := range rows {
+		o := newObservation(r)
+		o.Station = loc.station(c.stationOf(collection.Stations, r.Location, WeatherStation))
+		obs = append(obs, o)
+	}
+
+	return obs, nil
+}
+
+// parseFeatureCollection parses a response in any of the ResponseFormats
 func parseFeatureCollection(data []byte) (simpleFeatureCollection, error) {
+	switch detectFormat(data) {
+	case MultiPointCoverageFormat:
+		return parseMultiPointCoverage(data)
+	case TimeValuePairFormat:
+		return parseTimeValuePair(data)
+	}
+
 	var collection simpleFeatureCollection
 
 	if err := xml.Unmarshal(data, &collection); err != nil {
-		return simpleFeatureCollection{}, errors.New("virhe parsittaessa havaintoja")
+		return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
 	}
 
 	return collection, nil
 }
 
-func extractLatestObservations(collection simpleFeatureCollection, measures []string) observations {
-	observations := make(map[time.Time]map[string]map[string]float64)
+// groupElements groups the elements of a collection by time and location.
+// Times are sorted newest first and locations kept in the order of the
+// response, i.e. nearest first.
+func groupElements(collection simpleFeatureCollection) ([]time.Time, []string, map[time.Time]map[string]observations) {
+	grouped := make(map[time.Time]map[string]observations)
 	times := make([]time.Time, 0)
 	locations := make([]string, 0)
 
 	for _, obs := range collection.Elements {
-		if observations[obs.Time] == nil {
+		if grouped[obs.Time] == nil {
 			times = append(times, obs.Time)
-			observations[obs.Time] = make(map[string]map[string]float64)
+			grouped[obs.Time] = make(map[string]observations)
 		}
-		if observations[obs.Time][obs.Location] == nil {
+		if grouped[obs.Time][obs.Location] == nil {
 			if !slices.Contains(locations, obs.Location) {
 				locations = append(locations, obs.Location)
 			}
-			observations[obs.Time][obs.Location] = make(map[string]float64)
+			grouped[obs.Time][obs.Location] = make(observations)
 		}
-		observations[obs.Time][obs.Location][obs.Parameter] = obs.Value
+		grouped[obs.Time][obs.Location][obs.Parameter] = obs.Value
 	}
 
 	sort.Slice(times, func(i, j int) bool {
 		return times[i].After(times[j])
 	})
 
-	latestObs := make(map[string]float64)
+	return times, locations, grouped
+}
+
+// extractLatestObservations returns the row with the most measurements,
+// preferring newer rows and the nearest station on ties
+func extractLatestObservations(collection simpleFeatureCollection, measures []string) (row, bool) {
+	times, locations, observations := groupElements(collection)
+
+	var latest row
+	fewestNans := len(measures)
 	for _, timeIndex := range times {
 		for _, locationIndex := range locations {
-			if countNanMeasures(observations[timeIndex][locationIndex], measures) != len(measures) {
-				latestObs = observations[timeIndex][locationIndex]
+			obs, ok := observations[timeIndex][locationIndex]
+			if !ok {
+				continue
+			}
+			if nans := countNanMeasures(obs, measures); nans < fewestNans {
+				fewestNans = nans
+				latest = row{Location: locationIndex, Time: timeIndex, Values: obs, Quality: collection.Quality[locationIndex][timeIndex]}
 			}
 		}
 	}
 
-	return latestObs
-}
-
-// getObservations does a HTTP GET request against FMI's API to fetch data
-// for a place
-func getObservations(place string) (observations, error) {
-	/*  Parameters:
-	name		label				measure
-	t2m			Air Temperature		degC
-	ws_10min	Wind Speed			m/s
-	wg_10min	Gust Speed			m/s
-	wd_10min	Wind Direction		degrees
-	rh			Relative humidity	%
-	td			Dew-point temp.		degC
-	r_1h		Precipitation amt	mm
-	ri_10min	Precip. intensity	mm/h
-	snow_aws	Snow depth			cm
-				-1 = no snow, 0 = snow in vicinity
-	p_sea		Pressure (msl)		hPa
-	vis			Visibility			m
-	n_man		Cloud cover			1/8
-	wawa		Present weather		code (00-99)
-				see: https://www.wmo.int/pages/prog/www/WMOCodes/WMO306_vI1/Publications/2017update/Sel9.pdf
-	*/
-	measures := []string{"t2m", "ws_10min", "wg_10min", "wd_10min", "rh", "r_1h", "ri_10min", "snow_aws", "n_man", "td", "glob_u"}
+	return latest, fewestNans < len(measures)
+}
+
+// extractNearestObservations returns the newest row with measurements of
+// the nearest station, which is the first one in the collection
+func extractNearestObservations(collection simpleFeatureCollection, measures []string) (row, bool) {
+	times, locations, observations := groupElements(collection)
 
+	for _, locationIndex := range locations {
+		for _, timeIndex := range times {
+			obs, ok := observations[timeIndex][locationIndex]
+			if ok && countNanMeasures(obs, measures) < len(measures) {
+				return row{Location: locationIndex, Time: timeIndex, Values: obs, Quality: collection.Quality[locationIndex][timeIndex]}, true
+			}
+		}
+	}
+
+	return row{}, false
+}
+
+// extractNewestValues returns the newest valid value of each measure of the
+// nearest station with measurements, at the time of the newest value.
+// Unlike a single row, it is not held back by parameters observed less
+// often than others.
+func extractNewestValues(collection simpleFeatureCollection, measures []string) (row, bool) {
+	times, locations, grouped := groupElements(collection)
+
+	for _, locationIndex := range locations {
+		r := row{Location: locationIndex, Values: make(observations, len(measures))}
+		for _, measure := range measures {
+			r.Values[measure] = math.NaN()
+		}
+		found := false
+		for _, timeIndex := range times {
+			for _, measure := range measures {
+				v, ok := grouped[timeIndex][locationIndex][measure]
+				if !ok || math.IsNaN(v) || !math.IsNaN(r.Values[measure]) {
+					continue
+				}
+				r.Values[measure] = v
+				if !found {
+					r.Time = timeIndex
+					found = true
+				}
+			}
+		}
+		if found {
+			return r, true
+		}
+	}
+
+	return row{}, false
+}
+
+// extractStationObservations returns the row with the most measurements
+// for each station, preferring newer rows on ties
+func extractStationObservations(collection simpleFeatureCollection, measures []string) []row {
+	times, locations, observations := groupElements(collection)
+
+	rows := make([]row, 0, len(locations))
+	for _, locationIndex := range locations {
+		var latest row
+		fewestNans := len(measures)
+		for _, timeIndex := range times {
+			obs, ok := observations[timeIndex][locationIndex]
+			if !ok {
+				continue
+			}
+			if nans := countNanMeasures(obs, measures); nans < fewestNans {
+				fewestNans = nans
+				latest = row{Location: locationIndex, Time: timeIndex, Values: obs, Quality: collection.Quality[locationIndex][timeIndex]}
+			}
+		}
+		if fewestNans < len(measures) {
+			rows = append(rows, latest)
+		}
+	}
+
+	return rows
+}
+
+// measures are the weather parameters fetched for observations:
+//
+//	name      label              measure
+//	t2m       Air Temperature    degC
+//	ws_10min  Wind Speed         m/s
+//	wg_10min  Gust Speed         m/s
+//	wd_10min  Wind Direction     degrees
+//	rh        Relative humidity  %
+//	td        Dew-point temp.    degC
+//	r_1h      Precipitation amt  mm, -1 = no precipitation
+//	ri_10min  Precip. intensity  mm/h
+//	snow_aws  Snow depth         cm, -1 = no snow, 0 = snow in vicinity
+//	n_man     Cloud cover        1/8
+//	glob_u    Global radiation   W/m2
+//	wawa      Present weather    code (00-99)
+//	p_sea     Pressure (msl)     hPa
+//	vis       Visibility         m
+//
+// For the present weather codes, see:
+// https://www.wmo.int/pages/prog/www/WMOCodes/WMO306_vI1/Publications/2017update/Sel9.pdf
+var measures = []string{"t2m", "ws_10min", "wg_10min", "wd_10min", "rh", "r_1h", "ri_10min", "snow_aws", "n_man", "td", "glob_u", "wawa", "p_sea", "vis"}
+
+// observationQuery returns the WFS query for weather observations of the
+// given parameters at loc between start and end
+func observationQuery(loc Location, parameters []string, start, end time.Time, step time.Duration) url.Values {
 	q := url.Values{}
 	q.Set("service", "WFS")
 	q.Set("version", "2.0.0")
 	q.Set("request", "getFeature")
 	q.Set("storedquery_id", "fmi::observations::weather::simple")
 
-	q.Set("place", place)
-	q.Set("maxlocations", "2")
-	q.Set("parameters", strings.Join(measures, ","))
+	loc.set(q)
+	q.Set("parameters", strings.Join(parameters, ","))
+
+	q.Set("timestep", strconv.Itoa(int(step.Minutes())))
+	q.Set("starttime", start.UTC().Format(time.RFC3339))
+	q.Set("endtime", end.UTC().Format(time.RFC3339))
+
+	return q
+}
+
+// fetchFeatures does a HTTP GET request against FMI's API in the client's
+// response format and parses the returned feature collection
+func (c *Client) fetchFeatures(ctx context.Context, q url.Values) (simpleFeatureCollection, error) {
+	q = maps.Clone(q)
+	c.setFormat(q)
+
+	body, err := c.get(ctx, q)
+	if err != nil {
+		return simpleFeatureCollection{}, err
+	}
+
+	return parseFeatureCollection(body)
+}
 
+// latestWindow returns the time range the latest observations are
+// searched from
+func latestWindow() (time.Time, time.Time) {
 	// There should be data every 10 mins
-	q.Set("timestep", "10")
-	endTime := time.Now().UTC().Truncate(10 * time.Minute)
-	startTime := endTime.Add(-10 * time.Minute)
-	q.Set("starttime", startTime.Format(time.RFC3339))
-	q.Set("endtime", endTime.Format(time.RFC3339))
+	end := time.Now().UTC().Truncate(10 * time.Minute)
+	return end.Add(-10 * time.Minute), end
+}
 
-	endpoint := url.URL{
-		Scheme: "http",
-		Host:   "opendata.fmi.fi",
-		Path:   "/wfs",
+// latestQuery returns the WFS query for the latest observations of a
+// location. If maxLocations is positive, it overrides the number of
+// stations returned for a place or coordinate.
+func latestQuery(loc Location, maxLocations int) url.Values {
+	startTime, endTime := latestWindow()
+	q := observationQuery(loc, measures, startTime, endTime, 10*time.Minute)
+	if maxLocations > 0 && q.Has("maxlocations") {
+		q.Set("maxlocations", strconv.Itoa(maxLocations))
 	}
+	return q
+}
 
-	endpoint.RawQuery = q.Encode()
+// fetchLatest fetches the latest observations for a location and checks
+// their quality, see latestQuery
+func (c *Client) fetchLatest(ctx context.Context, loc Location, maxLocations int) (simpleFeatureCollection, error) {
+	if !loc.valid() {
+		return simpleFeatureCollection{}, ErrNoPlace
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	q := latestQuery(loc, maxLocations)
+	c.setNamedFormat(q)
+	collection, err := c.fetchFeatures(ctx, q)
 	if err != nil {
-		return nil, err
+		return simpleFeatureCollection{}, err
+	}
+	if collection.Matched == 0 || collection.Returned == 0 {
+		return simpleFeatureCollection{}, ErrNoData
 	}
+	c.newQualityChecker().checkCollection(&collection)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, errors.New("säähavaintoja ei saatu haettua")
+	return collection, nil
+}
+
+// fetchLatestOf fetches the observations of stored query id for a
+// location from the last window at the given timestep, picks the latest
+// with extract, and identifies their station of type t
+func (c *Client) fetchLatestOf(ctx context.Context, loc Location, id string, parameters []string, window, step time.Duration, t StationType, extract func(simpleFeatureCollection, []string) (row, bool)) (row, Station, error) {
+	if !loc.valid() {
+		return row{}, Station{}, ErrNoPlace
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	end := time.Now().UTC().Truncate(step)
+	q := observationQuery(loc, parameters, end.Add(-window), end, step)
+	q.Set("storedquery_id", id)
+	c.setNamedFormat(q)
+
+	collection, err := c.fetchFeatures(ctx, q)
 	if err != nil {
-		return nil, errors.New("virhe luettaessa havaintoja")
+		return row{}, Station{}, err
+	}
+	latest, ok := extract(collection, parameters)
+	if !ok {
+		return row{}, Station{}, ErrNoData
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		// If place parsing fails, returns 400 with OperationParsingFailed
-		return nil, errors.New("säähavaintopaikkaa ei löytynyt")
+	return latest, loc.station(c.stationOf(collection.Stations, latest.Location, t)), nil
+}
+
+// stationOf returns the station of type t at a location of a response,
+// from stations if the response named it and otherwise from the station
+// catalog if it has already been fetched. The catalog is not fetched for
+// the name alone.
+func (c *Client) stationOf(stations map[string]Station, location string, t StationType) Station {
+	if station, ok := stations[location]; ok {
+		return station
+	}
+	station := parseStation(location)
+	if catalog := c.cachedStations(); catalog != nil {
+		station = identifyStation(catalog, station, t)
 	}
+	return station
+}
 
-	collection, err := parseFeatureCollection(body)
-	if err != nil || collection.Matched == 0 || collection.Returned == 0 {
-		return nil, errors.New("säähavaintoja ei löytynyt")
+// getObservations fetches the latest observations for a location and
+// picks the best row, filling missing values from nearby stations when
+// merging. The stations named by the response are returned by location.
+func (c *Client) getObservations(ctx context.Context, loc Location) (row, map[string]Station, error) {
+	maxLocations := 0
+	if c.mergeDistance > 0 {
+		maxLocations = mergeLocations
+	}
+	collection, err := c.fetchLatest(ctx, loc, maxLocations)
+	if err != nil {
+		return row{}, nil, err
 	}
 
-	latestObs := extractLatestObservations(collection, measures)
-	if len(latestObs) == 0 {
-		return nil, errors.New("säähavaintoja ei löytynyt")
+	// When merging, the nearest station's newest row is only filled in, so
+	// that the observations are not taken over by a distant station
+	var latest row
+	var ok bool
+	if c.mergeDistance > 0 {
+		latest, ok = extractNearestObservations(collection, measures)
+	} else {
+		latest, ok = extractLatestObservations(collection, measures)
+	}
+	if !ok {
+		return row{}, nil, ErrNoData
+	}
+
+	if c.mergeDistance > 0 {
+		latest = mergeRows(latest, extractRows(collection), c.mergeDistance)
+	}
+	if p, ok := latest.Values["p_sea"]; ok && !math.IsNaN(p) && c.pressureTendency {
+		// The pressure tendency is auxiliary, so failures leave it out
+		q := latestQuery(loc, maxLocations)
+		if history, err := c.fetchPressureHistory(ctx, q, latest.Time, latest.Time); err == nil {
+			addPressureTendency(latest, history)
+		}
+	}
+	if c.mergeDistance > 0 {
+		c.nameSources(collection.Stations, latest.Sources)
 	}
 
-	return latestObs, nil
+	return latest, collection.Stations, nil
 }
 
 func countNanMeasures(obs observations, measures []string) int {
@@ -3,9 +3,41 @@ package fmi
 import (
 	"strings"
 	"testing"
+
+	"github.com/kari/fmi/fmitest"
 )
 
+var testStations = []fmitest.Station{
+	{
+		Name: "Helsinki Kaisaniemi", FMISID: 100971, WMO: 2978, Latitude: 60.17523, Longitude: 24.94459,
+		Values: map[string]float64{"t2m": 18.5, "ws_10min": 4, "wg_10min": 6, "wd_10min": 270, "rh": 56, "n_man": 4},
+	},
+	{
+		Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, WMO: 2974, Latitude: 60.32670, Longitude: 24.95675,
+		Values: map[string]float64{"t2m": 17.9, "snow_aws": -1},
+	},
+	{
+		Name: "Viitasaari Haapaniemi", Places: []string{"Pihtipudas"}, Latitude: 63.08, Longitude: 25.86,
+		Values: map[string]float64{"t2m": 15.1},
+	},
+}
+
+// useTestServer points the default client at a fake server for the
+// duration of a test
+func useTestServer(t *testing.T) *fmitest.Server {
+	srv := fmitest.NewServer(testStations...)
+	client := defaultClient
+	defaultClient = NewClient(WithBaseURL(srv.URL), WithWarningsURL(srv.WarningsURL()))
+	t.Cleanup(func() {
+		defaultClient = client
+		srv.Close()
+	})
+	return srv
+}
+
 func TestWeather(t *testing.T) {
+	useTestServer(t)
+
 	s, err := Weather("Helsinki")
 	if err != nil || !strings.Contains(s, "Helsinki") {
 		t.Errorf("Weather('Helsinki') should contain 'Helsinki', instead got '%s'", s)
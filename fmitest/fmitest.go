@@ -0,0 +1,724 @@
+// Package fmitest provides a fake FMI WFS server for hermetic tests.
+//
+// The server answers the stored queries used by package fmi with responses
+// generated from the stations added to it, in the simple (BsWfsElement),
+// multipointcoverage or timevaluepair format requested. It also serves
+// the stations as the fmi::ef::stations station catalog and lightning
+// strikes added with AddStrike, returns
+// WFS ExceptionReports like FMI does when a location is not found, and can
+// be made to fail or respond slowly.
+package fmitest
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WarningsPath is the path the server serves the CAP warnings feed at
+const WarningsPath = "/cap/feed/atom_fi-FI.xml"
+
+// StationsQuery is the stored query of the station catalog
+const StationsQuery = "fmi::ef::stations"
+
+// LightningQuery is the prefix of the lightning stored queries, which are
+// served in the simple format
+const LightningQuery = "fmi::observations::lightning"
+
+// Station is a station served by the fake server
+type Station struct {
+	Name      string
+	FMISID    int
+	WMO       int
+	GeoID     int
+	Places    []string // place names resolving to the station in addition to Name
+	Latitude  float64
+	Longitude float64
+
+	// Networks names the station networks the station belongs to in the
+	// station catalog, "Automaattinen sääasema" if empty
+	Networks []string
+
+	// Values holds the value of each parameter, returned for every time
+	// step. Parameters missing from Values are returned as NaN.
+	Values map[string]float64
+	// Func overrides Values when set, ok false returns NaN
+	Func func(parameter string, t time.Time) (value float64, ok bool)
+}
+
+// value returns the value of parameter at t
+func (s Station) value(parameter string, t time.Time) float64 {
+	if s.Func != nil {
+		if v, ok := s.Func(parameter, t); ok {
+			return v
+		}
+		return math.NaN()
+	}
+	if v, ok := s.Values[parameter]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+// matchesPlace reports whether a place name resolves to the station
+func (s Station) matchesPlace(place string) bool {
+	place = strings.ToLower(place)
+	if name := strings.ToLower(s.Name); name == place || strings.HasPrefix(name, place+" ") || strings.HasPrefix(name, place+"-") {
+		return true
+	}
+	for _, p := range s.Places {
+		if strings.ToLower(p) == place {
+			return true
+		}
+	}
+	return false
+}
+
+// Strike is a lightning strike served by the fake server
+type Strike struct {
+	Time         time.Time
+	Latitude     float64
+	Longitude    float64
+	PeakCurrent  float64 // kA
+	Multiplicity int
+	CloudToCloud bool
+}
+
+// failure is a canned error response
+type failure struct {
+	status int
+	code   string
+	texts  []string
+}
+
+// Server is a fake FMI WFS server
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	stations []Station
+	strikes  []Strike
+	warnings string
+	delay    time.Duration
+	failures []failure
+	retry    time.Duration
+	requests []url.Values
+}
+
+// NewServer starts a fake server with the given stations. The WFS
+// endpoint is served at URL, the caller should call Close when finished.
+func NewServer(stations ...Station) *Server {
+	s := &Server{stations: stations}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// WarningsURL returns the address of the CAP warnings feed
+func (s *Server) WarningsURL() string {
+	return s.Server.URL + WarningsPath
+}
+
+// AddStation adds a station to the server
+func (s *Server) AddStation(station Station) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stations = append(s.stations, station)
+}
+
+// AddStrike adds a lightning strike to the server
+func (s *Server) AddStrike(strike Strike) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strikes = append(s.strikes, strike)
+}
+
+// SetWarnings sets the CAP feed served at WarningsPath
+func (s *Server) SetWarnings(feed string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.warnings = feed
+}
+
+// SetDelay delays every response by d, or until the request is cancelled
+func (s *Server) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// FailNext makes the next n requests fail with status and an
+// ExceptionReport with code and texts
+func (s *Server) FailNext(n int, status int, code string, texts ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range n {
+		s.failures = append(s.failures, failure{status, code, texts})
+	}
+}
+
+// SetRetryAfter sets the Retry-After header of failed responses to d,
+// rounded up to whole seconds. 0 leaves the header out.
+func (s *Server) SetRetryAfter(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retry = d
+}
+
+// Requests returns the queries of the requests served so far
+func (s *Server) Requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Query())
+	delay := s.delay
+	var fail *failure
+	if len(s.failures) > 0 {
+		fail = &s.failures[0]
+		s.failures = s.failures[1:]
+	}
+	stations := slices.Clone(s.stations)
+	strikes := slices.Clone(s.strikes)
+	warnings := s.warnings
+	retry := s.retry
+	s.mu.Unlock()
+
+	if delay > 0 {
+		if !sleep(r.Context(), delay) {
+			return
+		}
+	}
+
+	if fail != nil {
+		if retry > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		}
+		writeException(w, fail.status, fail.code, "", fail.texts...)
+		return
+	}
+
+	if r.URL.Path == WarningsPath {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		fmt.Fprint(w, warnings)
+		return
+	}
+
+	q := r.URL.Query()
+	if !strings.EqualFold(q.Get("request"), "getFeature") || q.Get("storedquery_id") == "" {
+		writeException(w, http.StatusBadRequest, "MissingParameterValue", "storedquery_id", "No stored query given")
+		return
+	}
+
+	if q.Get("storedquery_id") == StationsQuery {
+		writeStations(w, stations)
+		return
+	}
+
+	if strings.HasPrefix(q.Get("storedquery_id"), LightningQuery) {
+		matched, err := selectStrikes(strikes, q)
+		if err != nil {
+			writeException(w, http.StatusBadRequest, "OperationParsingFailed", err.locator, err.texts...)
+			return
+		}
+		writeStrikes(w, matched, splitList(q.Get("parameters")))
+		return
+	}
+
+	matched, err := selectStations(stations, q)
+	if err != nil {
+		writeException(w, http.StatusBadRequest, "OperationParsingFailed", err.locator, err.texts...)
+		return
+	}
+
+	times, err := requestedTimes(q)
+	if err != nil {
+		writeException(w, http.StatusBadRequest, "OperationParsingFailed", err.locator, err.texts...)
+		return
+	}
+
+	parameters := splitList(q.Get("parameters"))
+	switch id := q.Get("storedquery_id"); {
+	case strings.HasSuffix(id, "::multipointcoverage"):
+		writeMultiPointCoverage(w, matched, times, parameters)
+	case strings.HasSuffix(id, "::timevaluepair"):
+		writeTimeValuePair(w, matched, times, parameters)
+	default:
+		writeSimple(w, matched, times, parameters)
+	}
+}
+
+// sleep waits for d, returning false if ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// queryError describes an invalid query parameter
+type queryError struct {
+	locator string
+	texts   []string
+}
+
+// selectStations returns the stations matching the location parameters
+// of a query, nearest first
+func selectStations(stations []Station, q url.Values) ([]Station, *queryError) {
+	maxLocations := 1
+	if n, err := strconv.Atoi(q.Get("maxlocations")); err == nil && n > 0 {
+		maxLocations = n
+	}
+
+	switch {
+	case q.Has("place"):
+		matched := make([]Station, 0)
+		for _, place := range q["place"] {
+			var found *Station
+			for _, st := range stations {
+				if st.matchesPlace(place) {
+					found = &st
+					break
+				}
+			}
+			if found == nil {
+				return nil, &queryError{place, []string{"Invalid parameter value!", "No locations found for the place with the requested language!"}}
+			}
+			matched = append(matched, nearest(stations, found.Latitude, found.Longitude, maxLocations)...)
+		}
+		return matched, nil
+	case q.Has("latlon"):
+		lat, lon, ok := parseLatLon(q.Get("latlon"))
+		if !ok {
+			return nil, &queryError{"latlon", []string{"Invalid parameter value!"}}
+		}
+		return nearest(stations, lat, lon, maxLocations), nil
+	case q.Has("fmisid"), q.Has("wmo"), q.Has("geoid"):
+		matched := make([]Station, 0)
+		for _, param := range []string{"fmisid", "wmo", "geoid"} {
+			for _, value := range q[param] {
+				id, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, &queryError{param, []string{"Invalid parameter value!"}}
+				}
+				for _, st := range stations {
+					if (param == "fmisid" && st.FMISID == id) || (param == "wmo" && st.WMO == id) || (param == "geoid" && st.GeoID == id) {
+						matched = append(matched, st)
+					}
+				}
+			}
+		}
+		return matched, nil
+	case q.Has("bbox"):
+		box := splitList(q.Get("bbox"))
+		if len(box) < 4 {
+			return nil, &queryError{"bbox", []string{"Invalid parameter value!"}}
+		}
+		coords := make([]float64, 4)
+		for i := range coords {
+			v, err := strconv.ParseFloat(box[i], 64)
+			if err != nil {
+				return nil, &queryError{"bbox", []string{"Invalid parameter value!"}}
+			}
+			coords[i] = v
+		}
+		matched := make([]Station, 0)
+		for _, st := range stations {
+			if st.Longitude >= coords[0] && st.Latitude >= coords[1] && st.Longitude <= coords[2] && st.Latitude <= coords[3] {
+				matched = append(matched, st)
+			}
+		}
+		return matched, nil
+	}
+
+	return nil, &queryError{"", []string{"No location given"}}
+}
+
+// nearest returns the n stations nearest to a coordinate
+func nearest(stations []Station, lat, lon float64, n int) []Station {
+	sorted := slices.Clone(stations)
+	slices.SortStableFunc(sorted, func(a, b Station) int {
+		da := math.Hypot(a.Latitude-lat, (a.Longitude-lon)*math.Cos(lat*math.Pi/180))
+		db := math.Hypot(b.Latitude-lat, (b.Longitude-lon)*math.Cos(lat*math.Pi/180))
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+func parseLatLon(s string) (float64, float64, bool) {
+	parts := splitList(s)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lon, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// selectStrikes returns the strikes inside the bounding box of a query
+// between its start and end time, inclusive
+func selectStrikes(strikes []Strike, q url.Values) ([]Strike, *queryError) {
+	box := splitList(q.Get("bbox"))
+	if len(box) < 4 {
+		return nil, &queryError{"bbox", []string{"Invalid parameter value!"}}
+	}
+	coords := make([]float64, 4)
+	for i := range coords {
+		v, err := strconv.ParseFloat(box[i], 64)
+		if err != nil {
+			return nil, &queryError{"bbox", []string{"Invalid parameter value!"}}
+		}
+		coords[i] = v
+	}
+	start, errStart := time.Parse(time.RFC3339, q.Get("starttime"))
+	end, errEnd := time.Parse(time.RFC3339, q.Get("endtime"))
+	if errStart != nil || errEnd != nil || end.Before(start) {
+		return nil, &queryError{"starttime", []string{"Invalid time interval!"}}
+	}
+
+	matched := make([]Strike, 0)
+	for _, st := range strikes {
+		if st.Longitude >= coords[0] && st.Latitude >= coords[1] && st.Longitude <= coords[2] && st.Latitude <= coords[3] &&
+			!st.Time.Before(start) && !st.Time.After(end) {
+			matched = append(matched, st)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b Strike) int {
+		return a.Time.Compare(b.Time)
+	})
+	return matched, nil
+}
+
+// requestedTimes returns the time steps between starttime and endtime
+func requestedTimes(q url.Values) ([]time.Time, *queryError) {
+	step := time.Hour
+	if q.Has("timestep") {
+		minutes, err := strconv.Atoi(q.Get("timestep"))
+		if err != nil || minutes <= 0 {
+			return nil, &queryError{"timestep", []string{"Invalid parameter value!"}}
+		}
+		step = time.Duration(minutes) * time.Minute
+	}
+
+	end := time.Now().UTC().Truncate(step)
+	if q.Has("endtime") {
+		t, err := time.Parse(time.RFC3339, q.Get("endtime"))
+		if err != nil {
+			return nil, &queryError{"endtime", []string{"Invalid parameter value!"}}
+		}
+		end = t
+	}
+	start := end.Add(-12 * time.Hour)
+	if q.Has("starttime") {
+		t, err := time.Parse(time.RFC3339, q.Get("starttime"))
+		if err != nil {
+			return nil, &queryError{"starttime", []string{"Invalid parameter value!"}}
+		}
+		start = t
+	}
+	if end.Before(start) {
+		return nil, &queryError{"endtime", []string{"Invalid time interval!"}}
+	}
+
+	times := make([]time.Time, 0)
+	for t := start.Truncate(step); !t.After(end); t = t.Add(step) {
+		if !t.Before(start) {
+			times = append(times, t)
+		}
+	}
+	return times, nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// writeSimple writes a BsWfsElement feature collection ordered by station,
+// time and parameter like FMI's simple stored queries
+func writeSimple(w http.ResponseWriter, stations []Station, times []time.Time, parameters []string) {
+	n := len(stations) * len(times) * len(parameters)
+
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="%s" numberMatched="%d" numberReturned="%d"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2"
+  xmlns:BsWfs="http://xml.fmi.fi/schema/wfs/2.0">
+`, time.Now().UTC().Format(time.RFC3339), n, n)
+
+	i := 0
+	for _, st := range stations {
+		for _, t := range times {
+			for _, p := range parameters {
+				i++
+				fmt.Fprintf(w, `  <wfs:member>
+    <BsWfs:BsWfsElement gml:id="BsWfsElement.1.1.%d">
+      <BsWfs:Location><gml:Point gml:id="BsWfsElementP.1.1.%d" srsDimension="2"><gml:pos>%s %s </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>%s</BsWfs:Time>
+      <BsWfs:ParameterName>%s</BsWfs:ParameterName>
+      <BsWfs:ParameterValue>%s</BsWfs:ParameterValue>
+    </BsWfs:BsWfsElement>
+  </wfs:member>
+`, i, i, formatFloat(st.Latitude), formatFloat(st.Longitude), t.UTC().Format(time.RFC3339), escape(p), formatFloat(st.value(p, t)))
+			}
+		}
+	}
+
+	fmt.Fprint(w, "</wfs:FeatureCollection>\n")
+}
+
+// writeStrikes writes lightning strikes as a BsWfsElement feature
+// collection like FMI's fmi::observations::lightning::simple stored query
+func writeStrikes(w http.ResponseWriter, strikes []Strike, parameters []string) {
+	n := len(strikes) * len(parameters)
+
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="%s" numberMatched="%d" numberReturned="%d"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2"
+  xmlns:BsWfs="http://xml.fmi.fi/schema/wfs/2.0">
+`, time.Now().UTC().Format(time.RFC3339), n, n)
+
+	i := 0
+	for _, st := range strikes {
+		for _, p := range parameters {
+			v := math.NaN()
+			switch p {
+			case "peak_current":
+				v = st.PeakCurrent
+			case "multiplicity":
+				v = float64(st.Multiplicity)
+			case "cloud_indicator":
+				v = 0
+				if st.CloudToCloud {
+					v = 1
+				}
+			}
+			i++
+			fmt.Fprintf(w, `  <wfs:member>
+    <BsWfs:BsWfsElement gml:id="BsWfsElement.1.1.%d">
+      <BsWfs:Location><gml:Point gml:id="BsWfsElementP.1.1.%d" srsDimension="2"><gml:pos>%s %s </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>%s</BsWfs:Time>
+      <BsWfs:ParameterName>%s</BsWfs:ParameterName>
+      <BsWfs:ParameterValue>%s</BsWfs:ParameterValue>
+    </BsWfs:BsWfsElement>
+  </wfs:member>
+`, i, i, formatFloat(st.Latitude), formatFloat(st.Longitude), st.Time.UTC().Format(time.RFC3339), escape(p), formatFloat(v))
+		}
+	}
+
+	fmt.Fprint(w, "</wfs:FeatureCollection>\n")
+}
+
+// writeCollectionStart writes the start of a feature collection of n
+// features
+func writeCollectionStart(w http.ResponseWriter, n int) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="%s" numberMatched="%d" numberReturned="%d"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2"
+  xmlns:om="http://www.opengis.net/om/2.0" xmlns:omso="http://inspire.ec.europa.eu/schemas/omso/3.0"
+  xmlns:sam="http://www.opengis.net/sampling/2.0" xmlns:sams="http://www.opengis.net/samplingSpatial/2.0"
+  xmlns:target="http://xml.fmi.fi/namespace/om/atmosphericfeatures/1.1" xmlns:gmlcov="http://www.opengis.net/gmlcov/1.0"
+  xmlns:swe="http://www.opengis.net/swe/2.0" xmlns:wml2="http://www.opengis.net/waterml/2.0"
+  xmlns:xlink="http://www.w3.org/1999/xlink">
+`, time.Now().UTC().Format(time.RFC3339), n, n)
+}
+
+// writeLocation writes a station as a target:Location member of a
+// LocationCollection
+func writeLocation(w http.ResponseWriter, st Station) {
+	fmt.Fprintf(w, `          <target:member><target:Location gml:id="obsloc-fmisid-%d-pos">
+            <gml:identifier codeSpace="http://xml.fmi.fi/namespace/stationcode/fmisid">%d</gml:identifier>
+            <gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/name">%s</gml:name>
+`, st.FMISID, st.FMISID, escape(st.Name))
+	if st.WMO != 0 {
+		fmt.Fprintf(w, "            <gml:name codeSpace=\"http://xml.fmi.fi/namespace/locationcode/wmo\">%d</gml:name>\n", st.WMO)
+	}
+	fmt.Fprintf(w, "            <target:representativePoint xlink:href=\"#point-%d\"/>\n          </target:Location></target:member>\n", st.FMISID)
+}
+
+// writeMultiPointCoverage writes a GridSeriesObservation with the values
+// of every station and time step like FMI's multipointcoverage stored
+// queries
+func writeMultiPointCoverage(w http.ResponseWriter, stations []Station, times []time.Time, parameters []string) {
+	if len(stations) == 0 || len(times) == 0 {
+		writeCollectionStart(w, 0)
+		fmt.Fprint(w, "</wfs:FeatureCollection>\n")
+		return
+	}
+	writeCollectionStart(w, 1)
+	fmt.Fprint(w, `  <wfs:member>
+    <omso:GridSeriesObservation gml:id="obs-obs-1-1">
+      <om:featureOfInterest><sams:SF_SpatialSamplingFeature gml:id="sampling-feature-1-1">
+        <sam:sampledFeature><target:LocationCollection gml:id="sampled-target-1-1">
+`)
+	for _, st := range stations {
+		writeLocation(w, st)
+	}
+	fmt.Fprint(w, `        </target:LocationCollection></sam:sampledFeature>
+        <sams:shape><gml:MultiPoint gml:id="mp-1-1">
+`)
+	for _, st := range stations {
+		fmt.Fprintf(w, "          <gml:pointMember><gml:Point gml:id=\"point-%d\" srsDimension=\"2\"><gml:name>%s</gml:name><gml:pos>%s %s </gml:pos></gml:Point></gml:pointMember>\n",
+			st.FMISID, escape(st.Name), formatFloat(st.Latitude), formatFloat(st.Longitude))
+	}
+	fmt.Fprint(w, `        </gml:MultiPoint></sams:shape>
+      </sams:SF_SpatialSamplingFeature></om:featureOfInterest>
+      <om:result><gmlcov:MultiPointCoverage gml:id="mpcv-1-1">
+        <gml:domainSet><gmlcov:SimpleMultiPoint gml:id="mp-1-1-positions" srsDimension="3"><gmlcov:positions>
+`)
+	for _, st := range stations {
+		for _, t := range times {
+			fmt.Fprintf(w, "                %s %s  %d\n", formatFloat(st.Latitude), formatFloat(st.Longitude), t.Unix())
+		}
+	}
+	fmt.Fprint(w, `                </gmlcov:positions></gmlcov:SimpleMultiPoint></gml:domainSet>
+        <gml:rangeSet><gml:DataBlock><gml:rangeParameters/><gml:doubleOrNilReasonTupleList>
+`)
+	for _, st := range stations {
+		for _, t := range times {
+			values := make([]string, 0, len(parameters))
+			for _, p := range parameters {
+				values = append(values, formatFloat(st.value(p, t)))
+			}
+			fmt.Fprintf(w, "                %s \n", strings.Join(values, " "))
+		}
+	}
+	fmt.Fprint(w, `                </gml:doubleOrNilReasonTupleList></gml:DataBlock></gml:rangeSet>
+        <gmlcov:rangeType><swe:DataRecord>
+`)
+	for _, p := range parameters {
+		fmt.Fprintf(w, "          <swe:field name=\"%s\" xlink:href=\"https://opendata.fmi.fi/meta?observableProperty=observation&amp;param=%s\"/>\n", escape(p), escape(p))
+	}
+	fmt.Fprint(w, `        </swe:DataRecord></gmlcov:rangeType>
+      </gmlcov:MultiPointCoverage></om:result>
+    </omso:GridSeriesObservation>
+  </wfs:member>
+</wfs:FeatureCollection>
+`)
+}
+
+// writeTimeValuePair writes a PointTimeSeriesObservation for every station
+// and parameter like FMI's timevaluepair stored queries
+func writeTimeValuePair(w http.ResponseWriter, stations []Station, times []time.Time, parameters []string) {
+	writeCollectionStart(w, len(stations)*len(parameters))
+	i := 0
+	for _, st := range stations {
+		for _, p := range parameters {
+			i++
+			fmt.Fprintf(w, `  <wfs:member>
+    <omso:PointTimeSeriesObservation gml:id="obs-obs-1-%d">
+      <om:observedProperty xlink:href="https://opendata.fmi.fi/meta?observableProperty=observation&amp;param=%s&amp;language=eng"/>
+      <om:featureOfInterest><sams:SF_SpatialSamplingFeature gml:id="fi-1-%d">
+        <sam:sampledFeature><target:LocationCollection gml:id="sampled-target-1-%d">
+`, i, escape(p), i, i)
+			writeLocation(w, st)
+			fmt.Fprintf(w, `        </target:LocationCollection></sam:sampledFeature>
+        <sams:shape><gml:Point gml:id="point-%d"><gml:name>%s</gml:name><gml:pos>%s %s </gml:pos></gml:Point></sams:shape>
+      </sams:SF_SpatialSamplingFeature></om:featureOfInterest>
+      <om:result><wml2:MeasurementTimeseries gml:id="obs-obs-1-%d-%s">
+`, st.FMISID, escape(st.Name), formatFloat(st.Latitude), formatFloat(st.Longitude), i, escape(p))
+			for _, t := range times {
+				fmt.Fprintf(w, "        <wml2:point><wml2:MeasurementTVP><wml2:time>%s</wml2:time><wml2:value>%s</wml2:value></wml2:MeasurementTVP></wml2:point>\n",
+					t.UTC().Format(time.RFC3339), formatFloat(st.value(p, t)))
+			}
+			fmt.Fprint(w, "      </wml2:MeasurementTimeseries></om:result>\n    </omso:PointTimeSeriesObservation>\n  </wfs:member>\n")
+		}
+	}
+	fmt.Fprint(w, "</wfs:FeatureCollection>\n")
+}
+
+// writeStations writes the station catalog as EnvironmentalMonitoringFacility
+// features like FMI's fmi::ef::stations stored query
+func writeStations(w http.ResponseWriter, stations []Station) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection timeStamp="%s" numberMatched="%d" numberReturned="%d"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2"
+  xmlns:ef="http://inspire.ec.europa.eu/schemas/ef/4.0" xmlns:xlink="http://www.w3.org/1999/xlink">
+`, time.Now().UTC().Format(time.RFC3339), len(stations), len(stations))
+
+	for _, st := range stations {
+		fmt.Fprintf(w, `  <wfs:member>
+    <ef:EnvironmentalMonitoringFacility gml:id="WFS-%d">
+      <gml:identifier codeSpace="http://xml.fmi.fi/namespace/stationcode/fmisid">%d</gml:identifier>
+      <gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/name">%s</gml:name>
+`, st.FMISID, st.FMISID, escape(st.Name))
+		if st.GeoID != 0 {
+			fmt.Fprintf(w, "      <gml:name codeSpace=\"http://xml.fmi.fi/namespace/locationcode/geoid\">%d</gml:name>\n", st.GeoID)
+		}
+		if st.WMO != 0 {
+			fmt.Fprintf(w, "      <gml:name codeSpace=\"http://xml.fmi.fi/namespace/locationcode/wmo\">%d</gml:name>\n", st.WMO)
+		}
+		fmt.Fprintf(w, `      <ef:name>%s</ef:name>
+      <ef:representativePoint><gml:Point gml:id="point-%d" srsDimension="2"><gml:pos>%s %s</gml:pos></gml:Point></ef:representativePoint>
+`, escape(st.Name), st.FMISID, formatFloat(st.Latitude), formatFloat(st.Longitude))
+		networks := st.Networks
+		if len(networks) == 0 {
+			networks = []string{"Automaattinen sääasema"}
+		}
+		for _, network := range networks {
+			fmt.Fprintf(w, "      <ef:belongsTo xlink:title=\"%s\"/>\n", escape(network))
+		}
+		fmt.Fprint(w, "    </ef:EnvironmentalMonitoringFacility>\n  </wfs:member>\n")
+	}
+
+	fmt.Fprint(w, "</wfs:FeatureCollection>\n")
+}
+
+// writeException writes a WFS ExceptionReport
+func writeException(w http.ResponseWriter, status int, code string, locator string, texts ...string) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ExceptionReport xmlns="http://www.opengis.net/ows/1.1" version="2.0.0">
+  <Exception exceptionCode="%s" locator="%s">
+`, escape(code), escape(locator))
+	for _, text := range texts {
+		fmt.Fprintf(w, "    <ExceptionText>%s</ExceptionText>\n", escape(text))
+	}
+	fmt.Fprint(w, "  </Exception>\n</ExceptionReport>\n")
+}
+
+func formatFloat(v float64) string {
+	if math.IsNaN(v) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
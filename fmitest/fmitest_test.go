@@ -0,0 +1,207 @@
+package fmitest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testStations = []Station{
+	{Name: "Helsinki Kaisaniemi", FMISID: 100971, WMO: 2978, Latitude: 60.17523, Longitude: 24.94459, Values: map[string]float64{"t2m": 12.5}},
+	{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, WMO: 2974, Latitude: 60.32670, Longitude: 24.95675, Values: map[string]float64{"t2m": 11.5}},
+	{Name: "Tampere Härmälä", FMISID: 101124, Places: []string{"Tampere"}, Latitude: 61.46561, Longitude: 23.74726},
+}
+
+func get(t *testing.T, srv *Server, q url.Values) (int, string) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/wfs?" + q.Encode())
+	if err != nil {
+		t.Fatalf("GET returned error %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func query(params ...string) url.Values {
+	q := url.Values{
+		"request":        {"getFeature"},
+		"storedquery_id": {"fmi::observations::weather::simple"},
+		"parameters":     {"t2m,rh"},
+		"timestep":       {"10"},
+		"starttime":      {"2024-01-01T12:00:00Z"},
+		"endtime":        {"2024-01-01T12:10:00Z"},
+	}
+	for i := 0; i+1 < len(params); i += 2 {
+		q.Set(params[i], params[i+1])
+	}
+	return q
+}
+
+func TestServerLocations(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+
+	var tests = []struct {
+		params   []string
+		elements int
+		contains string
+	}{
+		{[]string{"place", "helsinki", "maxlocations", "2"}, 8, "60.3267 24.95675"},
+		{[]string{"place", "Tampere"}, 4, "61.46561 23.74726"},
+		{[]string{"latlon", "60.3,24.9"}, 4, "60.3267 24.95675"},
+		{[]string{"fmisid", "100971"}, 4, "<BsWfs:ParameterValue>12.5</BsWfs:ParameterValue>"},
+		{[]string{"wmo", "2974"}, 4, "<BsWfs:ParameterValue>11.5</BsWfs:ParameterValue>"},
+		{[]string{"bbox", "24,60,25,61"}, 8, "<BsWfs:ParameterValue>NaN</BsWfs:ParameterValue>"},
+	}
+	for _, test := range tests {
+		status, body := get(t, srv, query(test.params...))
+		if status != http.StatusOK {
+			t.Errorf("%v returned HTTP %d", test.params, status)
+		}
+		if n := strings.Count(body, "<BsWfs:BsWfsElement "); n != test.elements {
+			t.Errorf("%v returned %d elements; want %d", test.params, n, test.elements)
+		}
+		if !strings.Contains(body, test.contains) {
+			t.Errorf("%v response should contain '%s'", test.params, test.contains)
+		}
+	}
+
+	status, body := get(t, srv, query("place", "Narnia"))
+	if status != http.StatusBadRequest || !strings.Contains(body, `exceptionCode="OperationParsingFailed"`) {
+		t.Errorf("unknown place returned HTTP %d '%s'; want an ExceptionReport", status, body)
+	}
+}
+
+func TestServerFormats(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+
+	var tests = []struct {
+		query    string
+		contains []string
+	}{
+		{"fmi::observations::weather::multipointcoverage", []string{
+			`<gml:pos>60.17523 24.94459 </gml:pos>`,
+			"60.17523 24.94459  1704110400\n",
+			"12.5 NaN \n",
+			`<swe:field name="rh"`,
+			`<gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/name">Helsinki Kaisaniemi</gml:name>`,
+		}},
+		{"fmi::observations::weather::timevaluepair", []string{
+			`numberReturned="2"`,
+			`param=t2m&amp;language=eng`,
+			`<wml2:time>2024-01-01T12:10:00Z</wml2:time><wml2:value>12.5</wml2:value>`,
+		}},
+	}
+	for _, test := range tests {
+		status, body := get(t, srv, query("storedquery_id", test.query, "fmisid", "100971"))
+		if status != http.StatusOK {
+			t.Errorf("%s returned HTTP %d", test.query, status)
+		}
+		for _, want := range test.contains {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s response should contain '%s'", test.query, want)
+			}
+		}
+	}
+
+	if _, body := get(t, srv, query("storedquery_id", "fmi::observations::weather::multipointcoverage", "bbox", "0,0,1,1")); !strings.Contains(body, `numberMatched="0"`) {
+		t.Errorf("multipointcoverage response without stations should match nothing")
+	}
+}
+
+func TestServerStations(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+
+	status, body := get(t, srv, url.Values{"request": {"getFeature"}, "storedquery_id": {StationsQuery}})
+	if status != http.StatusOK {
+		t.Fatalf("%s returned HTTP %d", StationsQuery, status)
+	}
+	for _, want := range []string{
+		`numberReturned="3"`,
+		`<gml:identifier codeSpace="http://xml.fmi.fi/namespace/stationcode/fmisid">100968</gml:identifier>`,
+		`<gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/wmo">2978</gml:name>`,
+		`<gml:pos>61.46561 23.74726</gml:pos>`,
+		`<ef:belongsTo xlink:title="Automaattinen sääasema"/>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%s response should contain '%s'", StationsQuery, want)
+		}
+	}
+}
+
+func TestServerFailures(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+
+	srv.FailNext(1, http.StatusServiceUnavailable, "ServiceUnavailable")
+	if status, _ := get(t, srv, query("place", "Helsinki")); status != http.StatusServiceUnavailable {
+		t.Errorf("FailNext() request returned HTTP %d; want 503", status)
+	}
+	if status, _ := get(t, srv, query("place", "Helsinki")); status != http.StatusOK {
+		t.Errorf("request after a failure returned HTTP %d; want 200", status)
+	}
+
+	srv.SetDelay(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/wfs?"+query("place", "Helsinki").Encode(), nil)
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Errorf("delayed request should time out")
+	}
+
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("Requests() returned %d requests; want 3", n)
+	}
+}
+
+func TestServerRetryAfter(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+	srv.SetRetryAfter(1500 * time.Millisecond)
+	srv.FailNext(1, http.StatusTooManyRequests, "")
+	resp, err := http.Get(srv.URL + "/wfs?" + query("place", "Helsinki").Encode())
+	if err != nil {
+		t.Fatalf("GET returned error %v", err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("Retry-After"); resp.StatusCode != http.StatusTooManyRequests || got != "2" {
+		t.Errorf("failed request returned HTTP %d with Retry-After '%s'; want 429 with '2'", resp.StatusCode, got)
+	}
+}
+
+func TestServerLightning(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	ts := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	srv.AddStrike(Strike{Time: ts.Add(5 * time.Minute), Latitude: 60.2, Longitude: 24.9, PeakCurrent: -12.5, Multiplicity: 2})
+	srv.AddStrike(Strike{Time: ts.Add(-time.Minute), Latitude: 60.2, Longitude: 24.9, PeakCurrent: 8})
+	srv.AddStrike(Strike{Time: ts.Add(10 * time.Minute), Latitude: 62.0, Longitude: 24.9, PeakCurrent: 8})
+
+	q := url.Values{
+		"request":        {"getFeature"},
+		"storedquery_id": {LightningQuery + "::simple"},
+		"parameters":     {"peak_current,multiplicity"},
+		"bbox":           {"24,60,26,61"},
+		"starttime":      {ts.Format(time.RFC3339)},
+		"endtime":        {ts.Add(time.Hour).Format(time.RFC3339)},
+	}
+	status, body := get(t, srv, q)
+	if status != http.StatusOK {
+		t.Fatalf("lightning query returned HTTP %d", status)
+	}
+	if !strings.Contains(body, `numberReturned="2"`) || !strings.Contains(body, "<BsWfs:ParameterValue>-12.5</BsWfs:ParameterValue>") {
+		t.Errorf("lightning query returned\n%s\nwant the strike inside the box and time range", body)
+	}
+
+	q.Del("bbox")
+	if status, _ := get(t, srv, q); status != http.StatusBadRequest {
+		t.Errorf("lightning query without bbox returned HTTP %d; want 400", status)
+	}
+}
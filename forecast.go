@@ -0,0 +1,211 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// forecastParameters maps the parameters of FMI's point forecasts to
+// Observation fields
+var forecastParameters = map[string]func(*Observation) *Value{
+	"Temperature":     func(o *Observation) *Value { return &o.Temperature },
+	"WindSpeedMS":     func(o *Observation) *Value { return &o.WindSpeed },
+	"WindGust":        func(o *Observation) *Value { return &o.WindGust },
+	"WindDirection":   func(o *Observation) *Value { return &o.WindDirection },
+	"Humidity":        func(o *Observation) *Value { return &o.Humidity },
+	"DewPoint":        func(o *Observation) *Value { return &o.DewPoint },
+	"Precipitation1h": func(o *Observation) *Value { return &o.Precipitation },
+	"TotalCloudCover": func(o *Observation) *Value { return &o.CloudCover },
+}
+
+var forecastMeasures = []string{"Temperature", "WindSpeedMS", "WindGust", "WindDirection", "Humidity", "DewPoint", "Precipitation1h", "TotalCloudCover"}
+
+// ForecastModel is the stored query used for forecasts
+type ForecastModel string
+
+const (
+	// ForecastEdited is the forecast edited by FMI's meteorologists
+	ForecastEdited ForecastModel = "fmi::forecast::edited::weather::scandinavia::point::simple"
+	// ForecastHarmonie is the raw HARMONIE numerical weather model
+	ForecastHarmonie ForecastModel = "fmi::forecast::harmonie::surface::point::simple"
+)
+
+// Forecast returns an hourly weather forecast for a place for the next
+// hours using the edited forecast
+func (c *Client) Forecast(ctx context.Context, place string, hours int) ([]Observation, error) {
+	return c.ForecastAt(ctx, Place(place), ForecastEdited, hours)
+}
+
+// ForecastAt returns an hourly weather forecast for a location for the
+// next hours using the given forecast model
+func (c *Client) ForecastAt(ctx context.Context, loc Location, model ForecastModel, hours int) ([]Observation, error) {
+	if !loc.valid() {
+		return nil, ErrNoPlace
+	}
+	if hours <= 0 {
+		return nil, fmt.Errorf("%w: ennusteen pituus on alle tunnin", ErrBadRequest)
+	}
+
+	q := url.Values{}
+	q.Set("service", "WFS")
+	q.Set("version", "2.0.0")
+	q.Set("request", "getFeature")
+	q.Set("storedquery_id", string(model))
+
+	loc.set(q)
+	q.Set("parameters", strings.Join(forecastMeasures, ","))
+
+	q.Set("timestep", "60")
+	startTime := time.Now().UTC().Truncate(time.Hour)
+	endTime := startTime.Add(time.Duration(hours) * time.Hour)
+	q.Set("starttime", startTime.Format(time.RFC3339))
+	q.Set("endtime", endTime.Format(time.RFC3339))
+
+	collection, err := c.fetchFeatures(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := extractRows(collection)
+	if len(rows) == 0 {
+		return nil, ErrNoData
+	}
+
+	forecast := make([]Observation, 0, len(rows))
+	for _, r := range rows {
+		// Forecasts are for a single point, the first one is the nearest
+		if r.Location != rows[0].Location {
+			break
+		}
+		o := convertRow(r, forecastParameters)
+		o.Station = loc.station(o.Station)
+		if o.CloudCover.Valid {
+			// Forecast cloud cover is in percent
+			o.CloudCover.Value = math.Round(o.CloudCover.Value / 100 * 8)
+		}
+		forecast = append(forecast, o)
+	}
+
+	return forecast, nil
+}
+
+// ForecastSummary returns a weather forecast for a place for the next hours
+// as a written description
+func (c *Client) ForecastSummary(ctx context.Context, place string, hours int) (string, error) {
+	tz, err := helsinki()
+	if err != nil {
+		return "", err
+	}
+	forecast, err := c.Forecast(ctx, place, hours)
+	if err != nil {
+		return "", err
+	}
+
+	return formatForecast(place, forecast, time.Now(), tz), nil
+}
+
+// helsinki returns the Finnish time zone, used for days and clock times.
+// It is loaded once from the system's time zone database, or from the one
+// embedded by programs importing time/tzdata. Falling back to another zone
+// would silently shift days and hours, so failing to load it is an error.
+var helsinki = sync.OnceValues(func() (*time.Location, error) {
+	tz, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		return nil, fmt.Errorf("aikavyöhykettä Europe/Helsinki ei löydy: %w", err)
+	}
+	return tz, nil
+})
+
+// formatForecast returns a string representation of a forecast at a
+// place summarised per day in time zone tz, relative to now
+func formatForecast(place string, forecast []Observation, now time.Time, tz *time.Location) string {
+	var output strings.Builder
+
+	c := cases.Title(language.Finnish)
+
+	fmt.Fprintf(&output, "Sääennuste paikassa %s: ", c.String(strings.ToLower(place)))
+
+	today := dayOf(now, tz)
+	days := make([]string, 0)
+	for len(forecast) > 0 {
+		day := dayOf(forecast[0].Time, tz)
+		n := 1
+		for n < len(forecast) && dayOf(forecast[n].Time, tz).Equal(day) {
+			n++
+		}
+		if s := formatForecastDay(day, today, forecast[:n]); s != "" {
+			days = append(days, s)
+		}
+		forecast = forecast[n:]
+	}
+	output.WriteString(strings.Join(days, "; "))
+
+	return output.String()
+}
+
+// formatForecastDay summarises the forecast for a single day
+func formatForecastDay(day time.Time, today time.Time, forecast []Observation) string {
+	minTemp, maxTemp := math.Inf(1), math.Inf(-1)
+	cloudSum, clouds, rain := 0.0, 0, 0.0
+	for _, o := range forecast {
+		if o.Temperature.Valid {
+			minTemp = math.Min(minTemp, o.Temperature.Value)
+			maxTemp = math.Max(maxTemp, o.Temperature.Value)
+		}
+		if o.CloudCover.Valid {
+			cloudSum += o.CloudCover.Value
+			clouds++
+		}
+		if o.Precipitation.Valid {
+			rain += o.Precipitation.Value
+		}
+	}
+
+	parts := make([]string, 0, 3)
+	if clouds > 0 {
+		if cover, ok := cloudCover(math.Round(cloudSum / float64(clouds))); ok {
+			parts = append(parts, cover)
+		}
+	}
+	if math.IsInf(minTemp, 1) {
+		return ""
+	} else if math.Round(minTemp) == math.Round(maxTemp) {
+		parts = append(parts, fmt.Sprintf("%.f°C", minTemp))
+	} else {
+		parts = append(parts, fmt.Sprintf("%.f…%.f°C", minTemp, maxTemp))
+	}
+	if rain >= 0.1 {
+		parts = append(parts, fmt.Sprintf("sadetta %.1f mm", rain))
+	}
+
+	return relativeDay(day, today) + " " + strings.Join(parts, ", ")
+}
+
+// dayOf returns the start of the day of t in time zone tz
+func dayOf(t time.Time, tz *time.Location) time.Time {
+	t = t.In(tz)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tz)
+}
+
+var weekdays = [...]string{"sunnuntaina", "maanantaina", "tiistaina", "keskiviikkona", "torstaina", "perjantaina", "lauantaina"}
+
+// relativeDay names a day relative to today, e.g. "huomenna"
+func relativeDay(day time.Time, today time.Time) string {
+	switch int(math.Round(day.Sub(today).Hours() / 24)) {
+	case 0:
+		return "tänään"
+	case 1:
+		return "huomenna"
+	case 2:
+		return "ylihuomenna"
+	}
+	return weekdays[day.Weekday()]
+}
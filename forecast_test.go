@@ -0,0 +1,111 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// finnishTime is the Finnish time zone used by formatting
+var finnishTime = func() *time.Location {
+	tz, err := helsinki()
+	if err != nil {
+		panic(err)
+	}
+	return tz
+}()
+
+func TestFormatForecast(t *testing.T) {
+	now := time.Date(2024, 5, 6, 18, 0, 0, 0, finnishTime)
+	hour := func(day, h int, temp, cover, rain float64) Observation {
+		return Observation{
+			Time:          time.Date(2024, 5, 6+day, h, 0, 0, 0, finnishTime),
+			Temperature:   Value{temp, true},
+			CloudCover:    Value{cover, true},
+			Precipitation: Value{rain, true},
+		}
+	}
+
+	var tests = []struct {
+		forecast []Observation
+		s        string
+	}{
+		{nil, "Sääennuste paikassa Helsinki: "},
+		{[]Observation{hour(0, 20, 7, 0, 0)}, "Sääennuste paikassa Helsinki: tänään selkeää, 7°C"},
+		{
+			[]Observation{hour(0, 22, 7, 0, 0), hour(0, 23, 6, 1, 0), hour(1, 0, 5, 8, 0.4), hour(1, 12, 8, 8, 0.3)},
+			"Sääennuste paikassa Helsinki: tänään selkeää, 6…7°C; huomenna pilvistä, 5…8°C, sadetta 0.7 mm",
+		},
+		{[]Observation{hour(4, 12, 12.4, 4, 0)}, "Sääennuste paikassa Helsinki: perjantaina puolipilvistä, 12°C"},
+		{[]Observation{{Time: now}}, "Sääennuste paikassa Helsinki: "},
+	}
+	for _, test := range tests {
+		got := formatForecast("helsinki", test.forecast, now, finnishTime)
+		if got != test.s {
+			t.Errorf("got '%s', wanted '%s'", got, test.s)
+		}
+	}
+}
+
+func TestRelativeDay(t *testing.T) {
+	today := time.Date(2024, 5, 6, 0, 0, 0, 0, finnishTime)
+	var tests = []struct {
+		days int
+		s    string
+	}{
+		{0, "tänään"},
+		{1, "huomenna"},
+		{2, "ylihuomenna"},
+		{3, "torstaina"},
+		{6, "sunnuntaina"},
+	}
+	for _, test := range tests {
+		got := relativeDay(today.AddDate(0, 0, test.days), today)
+		if got != test.s {
+			t.Errorf("relativeDay(+%d) = '%s'; want '%s'", test.days, got, test.s)
+		}
+	}
+}
+
+func TestForecast(t *testing.T) {
+	var storedQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storedQuery = r.URL.Query().Get("storedquery_id")
+		start, _ := time.Parse(time.RFC3339, r.URL.Query().Get("starttime"))
+		fmt.Fprint(w, `<wfs:FeatureCollection numberMatched="4" numberReturned="4">`)
+		for h := range 2 {
+			for _, p := range []struct {
+				name  string
+				value float64
+			}{{"Temperature", 5 + float64(h)}, {"TotalCloudCover", 100}} {
+				fmt.Fprintf(w, `<wfs:member><BsWfs:BsWfsElement>
+					<BsWfs:Location><gml:Point><gml:pos>60.17 24.94 </gml:pos></gml:Point></BsWfs:Location>
+					<BsWfs:Time>%s</BsWfs:Time>
+					<BsWfs:ParameterName>%s</BsWfs:ParameterName>
+					<BsWfs:ParameterValue>%f</BsWfs:ParameterValue>
+					</BsWfs:BsWfsElement></wfs:member>`, start.Add(time.Duration(h)*time.Hour).Format(time.RFC3339), p.name, p.value)
+			}
+		}
+		fmt.Fprint(w, `</wfs:FeatureCollection>`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	forecast, err := c.Forecast(context.Background(), "Helsinki", 1)
+	if err != nil {
+		t.Fatalf("Forecast() returned error %v", err)
+	}
+	if storedQuery != string(ForecastEdited) {
+		t.Errorf("storedquery_id = '%s'; want '%s'", storedQuery, ForecastEdited)
+	}
+	if len(forecast) != 2 || forecast[1].Temperature.Value != 6 || forecast[0].CloudCover.Value != 8 {
+		t.Errorf("Forecast() = %+v; want two hours, 5 and 6°C, cloud cover 8/8", forecast)
+	}
+
+	if _, err := c.Forecast(context.Background(), "Helsinki", 0); err == nil {
+		t.Errorf("Forecast() for zero hours should return an error")
+	}
+}
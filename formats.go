@@ -0,0 +1,279 @@
+package fmi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseFormat is the format of FMI's stored query responses. All
+// formats are parsed into the same results.
+type ResponseFormat string
+
+const (
+	// AutoFormat uses SimpleFormat for small queries and
+	// MultiPointCoverageFormat for large ones and for the latest
+	// observations, whose stations are named
+	AutoFormat ResponseFormat = ""
+	// SimpleFormat has an element for every value, with its location,
+	// time and parameter name
+	SimpleFormat ResponseFormat = "simple"
+	// MultiPointCoverageFormat lists locations and times once, followed
+	// by a block of values. It is the most compact format.
+	MultiPointCoverageFormat ResponseFormat = "multipointcoverage"
+	// TimeValuePairFormat has a time series for every parameter and
+	// station
+	TimeValuePairFormat ResponseFormat = "timevaluepair"
+)
+
+// simpleMaxElements is the largest expected number of values AutoFormat
+// fetches in the simple format
+const simpleMaxElements = 1000
+
+// bboxLocations is the number of stations a bounding box is expected to
+// match when estimating the size of a response
+const bboxLocations = 50
+
+// WithResponseFormat sets the format of stored query responses,
+// AutoFormat by default
+func WithResponseFormat(f ResponseFormat) Option {
+	return func(c *Client) {
+		c.format = f
+	}
+}
+
+// setFormat replaces the simple format of the stored query in q with the
+// client's format, choosing one by the expected size of the response if
+// the format is AutoFormat
+func (c *Client) setFormat(q url.Values) {
+	id := q.Get("storedquery_id")
+	if !strings.HasSuffix(id, "::"+string(SimpleFormat)) {
+		return
+	}
+	format := c.format
+	if format == AutoFormat {
+		format = SimpleFormat
+		if estimateElements(q) > simpleMaxElements {
+			format = MultiPointCoverageFormat
+		}
+	}
+	q.Set("storedquery_id", strings.TrimSuffix(id, string(SimpleFormat))+string(format))
+}
+
+// setNamedFormat replaces the simple format of the stored query in q with
+// MultiPointCoverageFormat if the client's format is AutoFormat, so that
+// the response names its stations
+func (c *Client) setNamedFormat(q url.Values) {
+	id := q.Get("storedquery_id")
+	if c.format == AutoFormat && strings.HasSuffix(id, "::"+string(SimpleFormat)) {
+		q.Set("storedquery_id", strings.TrimSuffix(id, string(SimpleFormat))+string(MultiPointCoverageFormat))
+	}
+}
+
+// estimateElements estimates the number of values a query returns
+func estimateElements(q url.Values) int {
+	steps := 1
+	start, errStart := time.Parse(time.RFC3339, q.Get("starttime"))
+	end, errEnd := time.Parse(time.RFC3339, q.Get("endtime"))
+	minutes, errStep := strconv.Atoi(q.Get("timestep"))
+	if errStart == nil && errEnd == nil && errStep == nil && minutes > 0 {
+		steps = int(end.Sub(start)/(time.Duration(minutes)*time.Minute)) + 1
+	}
+
+	parameters := max(len(strings.Split(q.Get("parameters"), ",")), 1)
+
+	locations := 0
+	for _, param := range []string{"place", "latlon", "fmisid", "wmo", "geoid"} {
+		locations += len(q[param])
+	}
+	if n, err := strconv.Atoi(q.Get("maxlocations")); err == nil && n > 1 {
+		locations *= n
+	}
+	if q.Has("bbox") {
+		locations += bboxLocations
+	}
+
+	return steps * parameters * max(locations, 1)
+}
+
+// detectFormat returns the format of a response from the first feature in
+// it, or SimpleFormat if there are none
+func detectFormat(data []byte) ResponseFormat {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return SimpleFormat
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "BsWfsElement":
+				return SimpleFormat
+			case "GridSeriesObservation":
+				return MultiPointCoverageFormat
+			case "PointTimeSeriesObservation":
+				return TimeValuePairFormat
+			}
+		}
+	}
+}
+
+// formatLocation formats a position like the simple format does, so rows
+// are keyed the same in every format
+func formatLocation(lat, lon string) string {
+	return lat + " " + lon + " "
+}
+
+// sampledLocation is a station of a multipointcoverage or timevaluepair
+// response, described like the stations of the station catalog
+type sampledLocation struct {
+	stationFacility
+	Point struct {
+		Href string `xml:"href,attr"`
+	} `xml:"representativePoint"`
+}
+
+type multiPointCoverageCollection struct {
+	Timestamp    time.Time `xml:"timeStamp,attr"`
+	Returned     int       `xml:"numberReturned,attr"`
+	Matched      int       `xml:"numberMatched,attr"`
+	Observations []struct {
+		Locations []sampledLocation `xml:"featureOfInterest>SF_SpatialSamplingFeature>sampledFeature>LocationCollection>member>Location"`
+		Points    []struct {
+			ID  string `xml:"id,attr"`
+			Pos string `xml:"pos"`
+		} `xml:"featureOfInterest>SF_SpatialSamplingFeature>shape>MultiPoint>pointMember>Point"`
+		Positions string `xml:"result>MultiPointCoverage>domainSet>SimpleMultiPoint>positions"`
+		Values    string `xml:"result>MultiPointCoverage>rangeSet>DataBlock>doubleOrNilReasonTupleList"`
+		Fields    []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"result>MultiPointCoverage>rangeType>DataRecord>field"`
+	} `xml:"member>GridSeriesObservation"`
+}
+
+// parseMultiPointCoverage parses a multipointcoverage response. Every
+// position of the domain set has a tuple of values in the range set, one
+// for each field.
+func parseMultiPointCoverage(data []byte) (simpleFeatureCollection, error) {
+	var mpc multiPointCoverageCollection
+	if err := xml.Unmarshal(data, &mpc); err != nil {
+		return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	collection := simpleFeatureCollection{
+		Timestamp: mpc.Timestamp,
+		Returned:  mpc.Returned,
+		Matched:   mpc.Matched,
+		Stations:  make(map[string]Station),
+	}
+	for _, obs := range mpc.Observations {
+		points := make(map[string]string, len(obs.Points))
+		for _, p := range obs.Points {
+			points["#"+p.ID] = p.Pos
+		}
+		for _, l := range obs.Locations {
+			if pos, ok := points[l.Point.Href]; ok {
+				l.Position = pos
+				s := l.station()
+				collection.Stations[formatLocation(splitPosition(pos))] = s
+			}
+		}
+
+		positions := strings.Fields(obs.Positions)
+		values := strings.Fields(obs.Values)
+		if len(positions)%3 != 0 || len(values) != len(positions)/3*len(obs.Fields) {
+			return simpleFeatureCollection{}, fmt.Errorf("%w: %d arvoa %d sijainnille ja %d parametrille", ErrParse, len(values), len(positions)/3, len(obs.Fields))
+		}
+		for i := 0; i < len(positions)/3; i++ {
+			epoch, err := strconv.ParseInt(positions[i*3+2], 10, 64)
+			if err != nil {
+				return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
+			}
+			location := formatLocation(positions[i*3], positions[i*3+1])
+			t := time.Unix(epoch, 0).UTC()
+			for j, field := range obs.Fields {
+				v, err := strconv.ParseFloat(values[i*len(obs.Fields)+j], 64)
+				if err != nil {
+					return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
+				}
+				collection.Elements = append(collection.Elements, observation{Location: location, Time: t, Parameter: field.Name, Value: v})
+			}
+		}
+	}
+	return collection, nil
+}
+
+type timeValuePairCollection struct {
+	Timestamp    time.Time `xml:"timeStamp,attr"`
+	Returned     int       `xml:"numberReturned,attr"`
+	Matched      int       `xml:"numberMatched,attr"`
+	Observations []struct {
+		Property struct {
+			Href string `xml:"href,attr"`
+		} `xml:"observedProperty"`
+		Locations []sampledLocation `xml:"featureOfInterest>SF_SpatialSamplingFeature>sampledFeature>LocationCollection>member>Location"`
+		Pos       string            `xml:"featureOfInterest>SF_SpatialSamplingFeature>shape>Point>pos"`
+		Series    struct {
+			ID     string `xml:"id,attr"`
+			Points []struct {
+				Time  time.Time `xml:"time"`
+				Value string    `xml:"value"`
+			} `xml:"point>MeasurementTVP"`
+		} `xml:"result>MeasurementTimeseries"`
+	} `xml:"member>PointTimeSeriesObservation"`
+}
+
+// parseTimeValuePair parses a timevaluepair response, which has a time
+// series for every parameter and station
+func parseTimeValuePair(data []byte) (simpleFeatureCollection, error) {
+	var tvp timeValuePairCollection
+	if err := xml.Unmarshal(data, &tvp); err != nil {
+		return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	collection := simpleFeatureCollection{
+		Timestamp: tvp.Timestamp,
+		Returned:  tvp.Returned,
+		Matched:   tvp.Matched,
+		Stations:  make(map[string]Station),
+	}
+	for _, obs := range tvp.Observations {
+		location := formatLocation(splitPosition(obs.Pos))
+		if len(obs.Locations) > 0 {
+			l := obs.Locations[0]
+			l.Position = obs.Pos
+			collection.Stations[location] = l.station()
+		}
+
+		parameter := ""
+		if u, err := url.Parse(obs.Property.Href); err == nil {
+			parameter = u.Query().Get("param")
+		}
+		if parameter == "" {
+			// Series ids end with the parameter, e.g. obs-obs-1-1-t2m
+			parameter = obs.Series.ID[strings.LastIndex(obs.Series.ID, "-")+1:]
+		}
+
+		for _, p := range obs.Series.Points {
+			v, err := strconv.ParseFloat(strings.TrimSpace(p.Value), 64)
+			if err != nil {
+				return simpleFeatureCollection{}, fmt.Errorf("%w: %w", ErrParse, err)
+			}
+			collection.Elements = append(collection.Elements, observation{Location: location, Time: p.Time, Parameter: parameter, Value: v})
+		}
+	}
+	return collection, nil
+}
+
+// splitPosition returns the latitude and longitude of a position
+func splitPosition(pos string) (string, string) {
+	fields := strings.Fields(pos)
+	if len(fields) < 2 {
+		return "", ""
+	}
+	return fields[0], fields[1]
+}
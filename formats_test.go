@@ -0,0 +1,79 @@
+package fmi
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestSetFormat(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		format ResponseFormat
+		q      url.Values
+		id     string
+	}{
+		{AutoFormat, observationQuery(Place("Turku"), measures, start, start.Add(10*time.Minute), 10*time.Minute), "fmi::observations::weather::simple"},
+		{AutoFormat, observationQuery(Place("Turku"), measures, start, start.Add(24*time.Hour), 10*time.Minute), "fmi::observations::weather::multipointcoverage"},
+		{AutoFormat, observationQuery(BBox(60, 24, 61, 25), measures, start, start.Add(10*time.Minute), 10*time.Minute), "fmi::observations::weather::multipointcoverage"},
+		{TimeValuePairFormat, observationQuery(FMISID(100971), measures, start, start, 10*time.Minute), "fmi::observations::weather::timevaluepair"},
+		{MultiPointCoverageFormat, url.Values{"storedquery_id": {"fmi::ef::stations"}}, "fmi::ef::stations"},
+	}
+	for _, test := range tests {
+		c := NewClient(WithResponseFormat(test.format))
+		c.setFormat(test.q)
+		if got := test.q.Get("storedquery_id"); got != test.id {
+			t.Errorf("setFormat(%q) with %d elements = '%s'; want '%s'", test.format, estimateElements(test.q), got, test.id)
+		}
+	}
+}
+
+func TestResponseFormats(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	loc := BBox(60, 24, 61, 25)
+
+	want, err := NewClient(WithBaseURL(srv.URL), WithResponseFormat(SimpleFormat)).TimeSeries(context.Background(), loc, start, end, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("TimeSeries() in the simple format returned error %v", err)
+	}
+	// Only the simple format lacks station names
+	for i := range want {
+		want[i].Station.Name = testStations[i].Name
+		want[i].Station.FMISID = testStations[i].FMISID
+		want[i].Station.WMO = testStations[i].WMO
+		for j := range want[i].Observations {
+			want[i].Observations[j].Station = want[i].Station
+		}
+	}
+
+	for _, format := range []ResponseFormat{MultiPointCoverageFormat, TimeValuePairFormat} {
+		got, err := NewClient(WithBaseURL(srv.URL), WithResponseFormat(format)).TimeSeries(context.Background(), loc, start, end, 10*time.Minute)
+		if err != nil {
+			t.Errorf("TimeSeries() in the %s format returned error %v", format, err)
+			continue
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("TimeSeries() in the %s format mismatch (-simple +%s):\n%s", format, format, diff)
+		}
+		if q := srv.Requests()[len(srv.Requests())-1]; q.Get("storedquery_id") != "fmi::observations::weather::"+string(format) {
+			t.Errorf("TimeSeries() requested %s; want the %s format", q.Get("storedquery_id"), format)
+		}
+	}
+
+	if _, err := parseMultiPointCoverage([]byte(`<FeatureCollection><member><GridSeriesObservation><result><MultiPointCoverage>
+		<domainSet><SimpleMultiPoint><positions>60.1 24.9 1704110400</positions></SimpleMultiPoint></domainSet>
+		<rangeSet><DataBlock><doubleOrNilReasonTupleList>1.0 2.0</doubleOrNilReasonTupleList></DataBlock></rangeSet>
+		<rangeType><DataRecord><field name="t2m"/></DataRecord></rangeType>
+	</MultiPointCoverage></result></GridSeriesObservation></member></FeatureCollection>`)); err == nil {
+		t.Errorf("parseMultiPointCoverage() should fail when values do not match the fields")
+	}
+}
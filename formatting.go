@@ -10,9 +10,9 @@ import (
 	"golang.org/x/text/language"
 )
 
-func formatTemperature(output io.Writer, observations observations) {
+func (l *locale) formatTemperature(output io.Writer, observations observations) {
 	if temp, ok := observations["t2m"]; ok && !math.IsNaN(temp) {
-		fmt.Fprintf(output, "lämpötila %.1f°C", temp)
+		fmt.Fprintf(output, l.temperature, temp)
 
 		feels := math.NaN()
 		if ws, ok := observations["ws_10min"]; ok {
@@ -25,50 +25,50 @@ func formatTemperature(output io.Writer, observations observations) {
 			}
 		}
 
-		if td, ok := observations["td"]; ok && temp > 20 {
-			if h, ok := humidexScale(Humidex(temp, td)); ok {
+		if td, ok := observations["td"]; ok && !math.IsNaN(td) && temp > 20 {
+			if h, ok := l.humidexScale(Humidex(temp, td)); ok {
 				if !math.IsNaN(feels) {
-					fmt.Fprintf(output, " (%s, tuntuu kuin %.1f°C)", h, feels)
+					fmt.Fprintf(output, " (%s, "+l.feelsLike+")", h, feels)
 				} else {
 					fmt.Fprintf(output, " (%s)", h)
 				}
 			} else if !math.IsNaN(feels) {
-				fmt.Fprintf(output, " (tuntuu kuin %.1f°C)", feels)
+				fmt.Fprintf(output, " ("+l.feelsLike+")", feels)
 			}
-		} else if ws, ok := observations["ws_10min"]; ok && temp <= 10 {
-			if wc, ok := windChillScale(WindChillFMI(temp, ws)); ok {
+		} else if ws, ok := observations["ws_10min"]; ok && !math.IsNaN(ws) && temp <= 10 {
+			if wc, ok := l.windChillScale(WindChillFMI(temp, ws)); ok {
 				if !math.IsNaN(feels) {
-					fmt.Fprintf(output, " (%s, tuntuu kuin %.1f°C)", wc, feels)
+					fmt.Fprintf(output, " (%s, "+l.feelsLike+")", wc, feels)
 				} else {
 					fmt.Fprintf(output, " (%s)", wc)
 				}
 			} else {
 				if !math.IsNaN(feels) {
-					fmt.Fprintf(output, " (tuntuu kuin %.1f°C)", feels)
+					fmt.Fprintf(output, " ("+l.feelsLike+")", feels)
 				}
 			}
 		} else if !math.IsNaN(feels) {
-			fmt.Fprintf(output, " (tuntuu kuin %.1f°C)", feels)
+			fmt.Fprintf(output, " ("+l.feelsLike+")", feels)
 		}
 	} else {
-		fmt.Fprint(output, "lämpötilatiedot puuttuvat")
+		fmt.Fprint(output, l.noTemperature)
 	}
 }
 
-func formatCloudCover(output io.Writer, observations observations) {
-	if cc, ok := observations["n_man"]; ok {
-		if cover, ok := cloudCover(cc); ok {
+func (l *locale) formatCloudCover(output io.Writer, observations observations) {
+	if cc, ok := observations["n_man"]; ok && !math.IsNaN(cc) {
+		if cover, ok := l.cloudCover(cc); ok {
 			fmt.Fprintf(output, ", %s", cover)
 		}
 	}
 }
 
-func formatWindSpeed(output io.Writer, observations observations) {
+func (l *locale) formatWindSpeed(output io.Writer, observations observations) {
 	if ws, ok := observations["ws_10min"]; ok && !math.IsNaN(ws) {
 		if wd, ok := observations["wd_10min"]; ok {
-			fmt.Fprintf(output, ", %s %.1f m/s", windSpeed(ws, wd), ws)
+			fmt.Fprintf(output, ", %s %.1f m/s", l.windSpeed(ws, wd), ws)
 		} else {
-			fmt.Fprintf(output, ", %s %.1f m/s", windSpeed(ws, math.NaN()), ws)
+			fmt.Fprintf(output, ", %s %.1f m/s", l.windSpeed(ws, math.NaN()), ws)
 		}
 		if wg, ok := observations["wg_10min"]; ok && !math.IsNaN(wg) {
 			fmt.Fprintf(output, " (%.1f m/s)", wg)
@@ -76,154 +76,216 @@ func formatWindSpeed(output io.Writer, observations observations) {
 	}
 }
 
-func formatHumidity(output io.Writer, observations observations) {
+func (l *locale) formatHumidity(output io.Writer, observations observations) {
 	if rh, ok := observations["rh"]; ok && !math.IsNaN(rh) {
-		fmt.Fprintf(output, ", ilmankosteus %.f%%", rh)
+		fmt.Fprintf(output, l.humidity, rh)
 	}
 }
 
-func formatRain(output io.Writer, observations observations) {
+func (l *locale) formatRain(output io.Writer, observations observations) {
 	if r, ok := observations["r_1h"]; ok && r >= 0 {
-		fmt.Fprintf(output, ", sateen määrä %.1f mm", r)
-		if ri, ok := observations["ri_10min"]; ok {
+		fmt.Fprintf(output, l.rain, r)
+		if ri, ok := observations["ri_10min"]; ok && !math.IsNaN(ri) {
 			fmt.Fprintf(output, " (%.1f mm/h)", ri)
 		}
 	}
 }
 
-func formatSnow(output io.Writer, observations observations) {
+func (l *locale) formatSnow(output io.Writer, observations observations) {
 	if snow, ok := observations["snow_aws"]; ok && snow >= 0 {
-		fmt.Fprintf(output, ", lumen syvyys %.f cm", snow)
+		fmt.Fprintf(output, l.snow, snow)
 	}
 }
 
+// FormatObservation returns a written description of an observation at a
+// place in the supported language best matching tag
+func FormatObservation(place string, o Observation, tag language.Tag) string {
+	return localeFor(tag).formatObservations(place, o.measures(), sourceNames(o.Sources))
+}
+
 // formatObservations returns a string representation of weather observations
-// at a place
+// at a place in Finnish
 func formatObservations(place string, observations observations) string {
+	return finnish.formatObservations(place, observations, nil)
+}
+
+// formatObservations returns a string representation of weather observations
+// at a place. Values merged from other stations are followed by the names
+// of the stations in sources, keyed by parameter.
+func (l *locale) formatObservations(place string, obs observations, sources map[string]string) string {
 	var output strings.Builder
 
-	c := cases.Title(language.Finnish)
+	c := cases.Title(l.tag)
 
-	fmt.Fprintf(&output, "Viimeisimmät säähavainnot paikassa %s: ", c.String(strings.ToLower(place)))
-	formatTemperature(&output, observations)
-	formatCloudCover(&output, observations)
-	formatWindSpeed(&output, observations)
-	formatHumidity(&output, observations)
-	formatRain(&output, observations)
-	formatSnow(&output, observations)
+	fmt.Fprintf(&output, l.header, c.String(strings.ToLower(place)))
+
+	sections := []struct {
+		format     func(io.Writer, observations)
+		parameters []string
+	}{
+		{l.formatTemperature, []string{"t2m"}},
+		{l.formatCloudCover, []string{"n_man"}},
+		{l.formatPresentWeather, []string{"wawa"}},
+		{l.formatVisibility, []string{"vis"}},
+		{l.formatWindSpeed, []string{"ws_10min", "wd_10min", "wg_10min"}},
+		{l.formatHumidity, []string{"rh"}},
+		{l.formatPressure, []string{"p_sea", pressureTendency}},
+		{l.formatRain, []string{"r_1h", "ri_10min"}},
+		{l.formatSnow, []string{"snow_aws"}},
+	}
+	for _, section := range sections {
+		n := output.Len()
+		section.format(&output, obs)
+		if output.Len() > n {
+			output.WriteString(formatSources(sources, section.parameters...))
+		}
+	}
 
 	return output.String()
 }
 
 // windSpeed takes wind speed s (m/s) and direction d (angle) and
-// returns a textual representation of them.
-// For reference, see: https://ilmatieteenlaitos.fi/tuulet
+// returns a textual representation of them in Finnish.
 func windSpeed(s float64, d float64) string {
+	return finnish.windSpeed(s, d)
+}
+
+// windClass classifies wind speed s (m/s) from calm (0) to hurricane (6),
+// or -1 if the speed is invalid.
+// For reference, see: https://ilmatieteenlaitos.fi/tuulet
+func windClass(s float64) int {
 	switch {
 	case s < 0:
-		return ""
+		return -1
 	case s < 1:
-		return "tyyntä"
+		return 0
 	case s <= 4:
-		return fmt.Sprintf("heikkoa %stuulta", windDirection(d))
+		return 1
 	case s <= 8:
-		return fmt.Sprintf("kohtalaista %stuulta", windDirection(d))
+		return 2
 	case s <= 14:
-		return fmt.Sprintf("navakkaa %stuulta", windDirection(d))
+		return 3
 	case s <= 21:
-		return fmt.Sprintf("kovaa %stuulta", windDirection(d))
+		return 4
 	case s < 33:
-		return "myrskyä"
+		return 5
 	case s >= 33:
-		return "hirmumyrskyä"
+		return 6
 	}
-	return ""
+	return -1
 }
 
 // windDirection takes a wind direction d in angles (0-360) and converts
-// it to a string representation. For reference, see:
-// https://ilmatieteenlaitos.fi/tuulet
+// it to a Finnish string representation.
 func windDirection(d float64) string {
+	return finnish.windDirection(d)
+}
+
+// compassSector converts a direction d in angles (0-360) to one of eight
+// compass points starting from north (0) clockwise, or -1 if the direction
+// is invalid. For reference, see:
+// https://ilmatieteenlaitos.fi/tuulet
+func compassSector(d float64) int {
 	switch {
 	case d < 0:
-		return ""
+		return -1
 	case d >= 0 && d <= 22.5:
-		return "pohjois"
+		return 0
 	case d < 67.5:
-		return "koillis"
+		return 1
 	case d <= 112.5:
-		return "itä"
+		return 2
 	case d < 157.5:
-		return "kaakkois"
+		return 3
 	case d <= 202.5:
-		return "etelä"
+		return 4
 	case d < 247.5:
-		return "lounais"
+		return 5
 	case d <= 292.5:
-		return "länsi"
+		return 6
 	case d < 337.5:
-		return "luoteis"
+		return 7
 	case d >= 337.5 && d <= 360:
-		return "pohjois"
+		return 0
 	}
-	return ""
+	return -1
 }
 
 // cloudCover converts the cloud cover measure (1/8) to textual format
-// using definitions at https://ilmatieteenlaitos.fi/pilvisyys
+// in Finnish
 func cloudCover(d float64) (string, bool) {
+	return finnish.cloudCover(d)
+}
+
+// cloudClass classifies the cloud cover measure (1/8) from clear (0) to
+// sky not visible (5), or -1 if the measure is invalid, using definitions
+// at https://ilmatieteenlaitos.fi/pilvisyys
+func cloudClass(d float64) int {
 	switch {
 	case d < 0:
-		return "", false
+		return -1
 	case d >= 0 && d <= 1:
-		return "selkeää", true
+		return 0
 	case d <= 3:
-		return "melko selkeää", true
+		return 1
 	case d <= 5:
-		return "puolipilvistä", true
+		return 2
 	case d <= 7:
-		return "melko pilvistä", true
+		return 3
 	case d <= 8:
-		return "pilvistä", true
+		return 4
 	case d == 9:
-		return "taivas ei näy", true
+		return 5
 	}
-	return "", false
+	return -1
 }
 
-// humidexScale converts humidex index h to a textual classification using
-// definitions from:
-// https://web.archive.org/web/20150319113439/http://ilmatieteenlaitos.fi/tietoa-helteen-tukaluudesta
+// humidexScale converts humidex index h to a textual classification in
+// Finnish
 func humidexScale(h float64) (string, bool) {
+	return finnish.humidexScale(h)
+}
+
+// humidexClass classifies humidex index h from comfortable (0) to very
+// oppressive (4), or -1 below the scale, using definitions from:
+// https://web.archive.org/web/20150319113439/http://ilmatieteenlaitos.fi/tietoa-helteen-tukaluudesta
+func humidexClass(h float64) int {
 	switch {
 	case h < 20:
-		return "", false
+		return -1
 	case h <= 26:
-		return "mukava", true
+		return 0
 	case h <= 30:
-		return "lämmin", true
+		return 1
 	case h <= 34:
-		return "kuuma", true
+		return 2
 	case h <= 40:
-		return "tukala", true
+		return 3
 	case h > 40:
-		return "erittäin tukala", true
+		return 4
 	}
-	return "", false
+	return -1
 }
 
-// windChillScale converts windChill index w to a textual representation using
-// classifications from https://fi.wikipedia.org/wiki/Pakkasen_purevuus
+// windChillScale converts windChill index w to a textual representation
+// in Finnish
 func windChillScale(w float64) (string, bool) {
+	return finnish.windChillScale(w)
+}
+
+// windChillClass classifies windChill index w from very cold (0) to high
+// risk of frostbite (2), or -1 above the scale, using classifications from
+// https://fi.wikipedia.org/wiki/Pakkasen_purevuus
+func windChillClass(w float64) int {
 	switch {
 	case w > -25:
-		return "", false
+		return -1
 	case w <= -60:
-		return "suuri paleltumisvaara", true
+		return 2
 	case w <= -35:
-		return "paleltumisvaara", true
+		return 1
 	case w <= -25:
-		return "erittäin kylmä", true
+		return 0
 	}
-	return "", false
+	return -1
 }
@@ -23,7 +23,7 @@ func TestFormatTemperature(t *testing.T) {
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatTemperature(buf, test.obs)
+		finnish.formatTemperature(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -42,7 +42,7 @@ func TestFormatCloudCover(t *testing.T) {
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatCloudCover(buf, test.obs)
+		finnish.formatCloudCover(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -63,7 +63,7 @@ func TestFormatWindSpeed(t *testing.T) {
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatWindSpeed(buf, test.obs)
+		finnish.formatWindSpeed(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -82,7 +82,7 @@ func TestFormatHumidity(t *testing.T) {
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatHumidity(buf, test.obs)
+		finnish.formatHumidity(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -98,11 +98,12 @@ func TestFormatRain(t *testing.T) {
 		{map[string]float64{}, ""},
 		{map[string]float64{"r_1h": 1.1}, ", sateen määrä 1.1 mm"},
 		{map[string]float64{"r_1h": 1.1, "ri_10min": 0.5}, ", sateen määrä 1.1 mm (0.5 mm/h)"},
+		{map[string]float64{"r_1h": 1.1, "ri_10min": math.NaN()}, ", sateen määrä 1.1 mm"},
 	}
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatRain(buf, test.obs)
+		finnish.formatRain(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -121,7 +122,7 @@ func TestFormatSnow(t *testing.T) {
 
 	buf := new(bytes.Buffer)
 	for _, test := range tests {
-		formatSnow(buf, test.obs)
+		finnish.formatSnow(buf, test.obs)
 		if buf.String() != test.s {
 			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
 		}
@@ -260,3 +261,19 @@ func TestWindSpeed(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatObservationsRejected(t *testing.T) {
+	// Quality control replaces rejected values with NaN
+	obs := observations{"r_1h": 0.4}
+	for _, measure := range measures {
+		if _, ok := obs[measure]; !ok {
+			obs[measure] = math.NaN()
+		}
+	}
+	obs[pressureTendency] = math.NaN()
+
+	want := "Viimeisimmät säähavainnot paikassa Turku: lämpötilatiedot puuttuvat, sateen määrä 0.4 mm"
+	if got := formatObservations("turku", obs); got != want {
+		t.Errorf("formatObservations() = '%s'; want '%s'", got, want)
+	}
+}
@@ -0,0 +1,300 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// lightningQuery is the stored query of located lightning strikes
+const lightningQuery = "fmi::observations::lightning::simple"
+
+var lightningMeasures = []string{"peak_current", "multiplicity", "cloud_indicator"}
+
+// DefaultLightningRadius is the radius in km ThunderSummary searches
+// strikes within
+const DefaultLightningRadius = 50.0
+
+// DefaultLightningWindow is how far back ThunderSummary searches strikes
+const DefaultLightningWindow = time.Hour
+
+// lightningTrendDistance is how much closer (km) the recent strikes must
+// be on average than the earlier ones for a storm to be approaching
+const lightningTrendDistance = 5.0
+
+// Strike is a lightning strike located by FMI's lightning detection network
+type Strike struct {
+	Time      time.Time `json:"time"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+
+	PeakCurrent  Value `json:"peak_current"`   // kA, negative for negative strikes
+	Multiplicity Value `json:"multiplicity"`   // strokes in the flash
+	CloudToCloud bool  `json:"cloud_to_cloud"` // within or between clouds rather than to ground
+
+	Distance float64 `json:"distance"` // km from the queried location
+	Bearing  float64 `json:"bearing"`  // degrees from the queried location
+}
+
+// LightningTrend tells whether a thunderstorm is approaching
+type LightningTrend int
+
+const (
+	// TrendUnknown is returned when there are too few strikes to tell
+	TrendUnknown LightningTrend = iota
+	// TrendApproaching means the recent strikes are closer than the
+	// earlier ones
+	TrendApproaching
+	// TrendSteady means the strikes stay at about the same distance
+	TrendSteady
+	// TrendReceding means the recent strikes are further than the
+	// earlier ones
+	TrendReceding
+)
+
+// LightningSummary aggregates the strikes near a location
+type LightningSummary struct {
+	Count   int
+	Nearest Strike // zero if Count is 0
+	Latest  Strike // zero if Count is 0
+	Trend   LightningTrend
+}
+
+// Lightning returns the lightning strikes within radius km of a location
+// during the last window, ordered by time. Places and GeoNames ids are
+// located by their nearest weather station. No strikes is not an error.
+func (c *Client) Lightning(ctx context.Context, loc Location, radius float64, window time.Duration) ([]Strike, error) {
+	end := time.Now().UTC().Truncate(time.Minute)
+	return c.lightning(ctx, loc, radius, end.Add(-window), end)
+}
+
+// LightningSummaryAt summarises the lightning strikes within radius km of a
+// location during the last window
+func (c *Client) LightningSummaryAt(ctx context.Context, loc Location, radius float64, window time.Duration) (LightningSummary, error) {
+	end := time.Now().UTC().Truncate(time.Minute)
+	strikes, err := c.lightning(ctx, loc, radius, end.Add(-window), end)
+	if err != nil {
+		return LightningSummary{}, err
+	}
+	return SummarizeLightning(strikes, end.Add(-window), end), nil
+}
+
+// ThunderSummary tells whether there is thunder near a place as a written
+// description in Finnish, looking for strikes within
+// DefaultLightningRadius during the last DefaultLightningWindow
+func (c *Client) ThunderSummary(ctx context.Context, place string) (string, error) {
+	if place == "" {
+		return "", ErrNoPlace
+	}
+
+	tz, err := helsinki()
+	if err != nil {
+		return "", err
+	}
+	s, err := c.LightningSummaryAt(ctx, Place(place), DefaultLightningRadius, DefaultLightningWindow)
+	if err != nil {
+		return "", err
+	}
+
+	return formatLightning(place, s, DefaultLightningRadius, DefaultLightningWindow, tz), nil
+}
+
+// lightning fetches the strikes within radius km of a location between
+// start and end
+func (c *Client) lightning(ctx context.Context, loc Location, radius float64, start, end time.Time) ([]Strike, error) {
+	if radius <= 0 {
+		return nil, fmt.Errorf("%w: säde ei ole positiivinen", ErrBadRequest)
+	}
+	lat, lon, err := c.coordinates(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored query only takes a bounding box, which is cut to a
+	// circle afterwards
+	dLat := radius / (earthRadius * math.Pi / 180)
+	dLon := dLat / math.Cos(lat*math.Pi/180)
+	q := observationQuery(BBox(lat-dLat, lon-dLon, lat+dLat, lon+dLon), lightningMeasures, start, end, time.Minute)
+	q.Set("storedquery_id", lightningQuery)
+	q.Del("timestep")
+
+	collection, err := c.fetchFeatures(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	strikes := make([]Strike, 0)
+	for _, r := range extractRows(collection) {
+		s := parseStation(r.Location)
+		strike := Strike{
+			Time:         r.Time,
+			Latitude:     s.Latitude,
+			Longitude:    s.Longitude,
+			CloudToCloud: r.Values["cloud_indicator"] == 1,
+			Distance:     Distance(lat, lon, s.Latitude, s.Longitude),
+			Bearing:      Bearing(lat, lon, s.Latitude, s.Longitude),
+		}
+		if strike.Distance > radius {
+			continue
+		}
+		if v, ok := r.Values["peak_current"]; ok && !math.IsNaN(v) {
+			strike.PeakCurrent = Value{v, true}
+		}
+		if v, ok := r.Values["multiplicity"]; ok && !math.IsNaN(v) {
+			strike.Multiplicity = Value{v, true}
+		}
+		strikes = append(strikes, strike)
+	}
+	slices.SortStableFunc(strikes, func(a, b Strike) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return strikes, nil
+}
+
+// coordinates returns the coordinates of a location. Places and GeoNames
+// ids are located by FMI as their nearest weather station, station ids
+// from the station catalog.
+func (c *Client) coordinates(ctx context.Context, loc Location) (float64, float64, error) {
+	switch loc.param {
+	case "latlon":
+		parts := strings.Split(loc.value, ",")
+		if len(parts) == 2 {
+			lat, errLat := strconv.ParseFloat(parts[0], 64)
+			lon, errLon := strconv.ParseFloat(parts[1], 64)
+			if errLat == nil && errLon == nil {
+				return lat, lon, nil
+			}
+		}
+	case "fmisid", "wmo":
+		catalog, err := c.catalog(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		id, _ := strconv.Atoi(loc.value)
+		find := catalog.ByFMISID
+		if loc.param == "wmo" {
+			find = catalog.ByWMO
+		}
+		if s, ok := find(id); ok {
+			return s.Latitude, s.Longitude, nil
+		}
+		return 0, 0, ErrPlaceNotFound
+	case "place", "geoid":
+		// The station's position is known even if it has no current
+		// observations, so the values are not looked at
+		collection, err := c.fetchLatest(ctx, loc, 1)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(collection.Elements) == 0 {
+			return 0, 0, ErrNoData
+		}
+		s := parseStation(collection.Elements[0].Location)
+		return s.Latitude, s.Longitude, nil
+	}
+	if !loc.valid() {
+		return 0, 0, ErrNoPlace
+	}
+	return 0, 0, fmt.Errorf("%w: sijainti ei ole paikka tai koordinaatti", ErrBadRequest)
+}
+
+// SummarizeLightning aggregates strikes found between start and end. The
+// trend compares the mean distance of the strikes of the latter half of
+// the time range to the former half.
+func SummarizeLightning(strikes []Strike, start, end time.Time) LightningSummary {
+	s := LightningSummary{Count: len(strikes)}
+	if len(strikes) == 0 {
+		return s
+	}
+
+	middle := start.Add(end.Sub(start) / 2)
+	var earlier, recent, earlierSum, recentSum float64
+	for i, strike := range strikes {
+		if i == 0 || strike.Distance < s.Nearest.Distance {
+			s.Nearest = strike
+		}
+		if i == 0 || strike.Time.After(s.Latest.Time) {
+			s.Latest = strike
+		}
+		if strike.Time.Before(middle) {
+			earlier++
+			earlierSum += strike.Distance
+		} else {
+			recent++
+			recentSum += strike.Distance
+		}
+	}
+
+	if earlier > 0 && recent > 0 {
+		switch d := recentSum/recent - earlierSum/earlier; {
+		case d < -lightningTrendDistance:
+			s.Trend = TrendApproaching
+		case d > lightningTrendDistance:
+			s.Trend = TrendReceding
+		default:
+			s.Trend = TrendSteady
+		}
+	}
+	return s
+}
+
+// lightningTrends describes the trends in Finnish
+var lightningTrends = map[LightningTrend]string{
+	TrendApproaching: "ukkonen lähestyy",
+	TrendSteady:      "ukkonen pysyy paikallaan",
+	TrendReceding:    "ukkonen loittonee",
+}
+
+// formatLightning returns a string representation of a lightning summary
+// for strikes within radius km during the last window at a place in
+// Finnish, with times in time zone tz
+func formatLightning(place string, s LightningSummary, radius float64, window time.Duration, tz *time.Location) string {
+	c := cases.Title(language.Finnish)
+	place = c.String(strings.ToLower(place))
+	period := formatLightningWindow(window)
+
+	if s.Count == 0 {
+		return fmt.Sprintf("Ei salamahavaintoja %.f km säteellä paikasta %s %s", radius, place, period)
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "Ukkosta lähellä paikkaa %s: ", place)
+	if s.Count == 1 {
+		output.WriteString("1 salama")
+	} else {
+		fmt.Fprintf(&output, "%d salamaa", s.Count)
+	}
+	fmt.Fprintf(&output, " %.f km säteellä %s", radius, period)
+
+	fmt.Fprintf(&output, ", lähin %.f km", s.Nearest.Distance)
+	if direction := finnish.windDirection(s.Nearest.Bearing); direction != "" {
+		output.WriteString(" " + direction + "puolella")
+	}
+	fmt.Fprintf(&output, " klo %s", s.Nearest.Time.In(tz).Format("15.04"))
+
+	if trend, ok := lightningTrends[s.Trend]; ok {
+		output.WriteString(", " + trend)
+	}
+
+	return output.String()
+}
+
+// formatLightningWindow describes the last window in Finnish, e.g.
+// "viimeisen tunnin aikana" or "viimeisen 30 minuutin aikana"
+func formatLightningWindow(window time.Duration) string {
+	switch {
+	case window == time.Hour:
+		return "viimeisen tunnin aikana"
+	case window%time.Hour == 0:
+		return fmt.Sprintf("viimeisen %d tunnin aikana", int(window.Hours()))
+	}
+	return fmt.Sprintf("viimeisen %d minuutin aikana", int(window.Minutes()))
+}
@@ -0,0 +1,164 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestSummarizeLightning(t *testing.T) {
+	start := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	strike := func(minutes int, distance float64) Strike {
+		return Strike{Time: start.Add(time.Duration(minutes) * time.Minute), Distance: distance}
+	}
+	var tests = []struct {
+		name    string
+		strikes []Strike
+		count   int
+		nearest float64
+		trend   LightningTrend
+	}{
+		{"no strikes", nil, 0, 0, TrendUnknown},
+		{"only recent", []Strike{strike(40, 30), strike(50, 20)}, 2, 20, TrendUnknown},
+		{"approaching", []Strike{strike(5, 45), strike(20, 38), strike(40, 25), strike(55, 12)}, 4, 12, TrendApproaching},
+		{"receding", []Strike{strike(5, 8), strike(50, 30)}, 2, 8, TrendReceding},
+		{"steady", []Strike{strike(10, 20), strike(20, 24), strike(45, 22)}, 3, 20, TrendSteady},
+	}
+	for _, test := range tests {
+		s := SummarizeLightning(test.strikes, start, end)
+		if s.Count != test.count || s.Nearest.Distance != test.nearest || s.Trend != test.trend {
+			t.Errorf("%s: SummarizeLightning() = %d strikes, nearest %v km, trend %d; want %d, %v km, %d", test.name, s.Count, s.Nearest.Distance, s.Trend, test.count, test.nearest, test.trend)
+		}
+		if test.count > 0 && !s.Latest.Time.Equal(test.strikes[len(test.strikes)-1].Time) {
+			t.Errorf("%s: SummarizeLightning().Latest = %v; want the last strike", test.name, s.Latest.Time)
+		}
+	}
+}
+
+func TestFormatLightning(t *testing.T) {
+	ts := time.Date(2024, 7, 1, 11, 32, 0, 0, time.UTC)
+	var tests = []struct {
+		s      LightningSummary
+		radius float64
+		window time.Duration
+		text   string
+	}{
+		{LightningSummary{}, 50, time.Hour, "Ei salamahavaintoja 50 km säteellä paikasta Helsinki viimeisen tunnin aikana"},
+		{LightningSummary{}, 20, 30 * time.Minute, "Ei salamahavaintoja 20 km säteellä paikasta Helsinki viimeisen 30 minuutin aikana"},
+		{
+			LightningSummary{Count: 1, Nearest: Strike{Time: ts, Distance: 31.6, Bearing: 10}}, 50, time.Hour,
+			"Ukkosta lähellä paikkaa Helsinki: 1 salama 50 km säteellä viimeisen tunnin aikana, lähin 32 km pohjoispuolella klo 14.32",
+		},
+		{
+			LightningSummary{Count: 12, Nearest: Strike{Time: ts, Distance: 8.2, Bearing: 225}, Trend: TrendApproaching}, 100, 3 * time.Hour,
+			"Ukkosta lähellä paikkaa Helsinki: 12 salamaa 100 km säteellä viimeisen 3 tunnin aikana, lähin 8 km lounaispuolella klo 14.32, ukkonen lähestyy",
+		},
+	}
+	for _, test := range tests {
+		if got := formatLightning("helsinki", test.s, test.radius, test.window, finnishTime); got != test.text {
+			t.Errorf("formatLightning(%v) = '%s'; want '%s'", test.s, got, test.text)
+		}
+	}
+}
+
+func TestClientLightning(t *testing.T) {
+	srv := fmitest.NewServer(fmitest.Station{Name: "Helsinki Kaisaniemi", FMISID: 100971, Latitude: 60.17523, Longitude: 24.94459, Values: map[string]float64{"t2m": 21}})
+	defer srv.Close()
+	now := time.Now().UTC().Truncate(time.Minute)
+	srv.AddStrike(fmitest.Strike{Time: now.Add(-50 * time.Minute), Latitude: 60.5, Longitude: 24.94459, PeakCurrent: -15, Multiplicity: 3})
+	srv.AddStrike(fmitest.Strike{Time: now.Add(-10 * time.Minute), Latitude: 60.1, Longitude: 24.7, PeakCurrent: 22, CloudToCloud: true})
+	// Inside the bounding box but outside the radius
+	srv.AddStrike(fmitest.Strike{Time: now.Add(-5 * time.Minute), Latitude: 60.6, Longitude: 25.7})
+	// Too old
+	srv.AddStrike(fmitest.Strike{Time: now.Add(-2 * time.Hour), Latitude: 60.2, Longitude: 24.9})
+
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	strikes, err := c.Lightning(ctx, LatLon(60.17523, 24.94459), 50, time.Hour)
+	if err != nil {
+		t.Fatalf("Lightning() returned error %v", err)
+	}
+	if len(strikes) != 2 {
+		t.Fatalf("Lightning() returned %d strikes; want 2", len(strikes))
+	}
+	first, second := strikes[0], strikes[1]
+	if first.PeakCurrent != (Value{-15, true}) || first.Multiplicity != (Value{3, true}) || first.CloudToCloud {
+		t.Errorf("Lightning()[0] = %+v; want a -15 kA ground strike of 3 strokes", first)
+	}
+	if compassSector(first.Bearing) != 0 || first.Distance < 36 || first.Distance > 37 {
+		t.Errorf("Lightning()[0] is %.1f km at %.f°; want about 36.5 km north", first.Distance, first.Bearing)
+	}
+	if !second.CloudToCloud || compassSector(second.Bearing) != 5 {
+		t.Errorf("Lightning()[1] = %+v; want a cloud strike to the southwest", second)
+	}
+
+	s, err := c.ThunderSummary(ctx, "Helsinki")
+	if err != nil {
+		t.Fatalf("ThunderSummary('Helsinki') returned error %v", err)
+	}
+	want := "Ukkosta lähellä paikkaa Helsinki: 2 salamaa 50 km säteellä viimeisen tunnin aikana, lähin 16 km lounaispuolella klo " +
+		now.Add(-10*time.Minute).In(finnishTime).Format("15.04") + ", ukkonen lähestyy"
+	if s != want {
+		t.Errorf("ThunderSummary('Helsinki') = '%s'; want '%s'", s, want)
+	}
+
+	if _, err := c.Lightning(ctx, BBox(60, 24, 61, 25), 50, time.Hour); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Lightning() for a bounding box returned error %v; want ErrBadRequest", err)
+	}
+}
+
+func TestCoordinates(t *testing.T) {
+	srv := fmitest.NewServer(append(testStations[:len(testStations):len(testStations)], fmitest.Station{
+		// No current observations, which does not matter for locating it
+		Name: "Ii Olhava", FMISID: 101786, GeoID: 656820, Latitude: 65.45, Longitude: 25.4,
+	})...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	var tests = []struct {
+		loc Location
+		lat float64
+		err error
+	}{
+		{Place("Helsinki"), 60.17523, nil},
+		// A municipality without a station of the same name
+		{Place("Pihtipudas"), 63.08, nil},
+		{Place("Ii"), 65.45, nil},
+		{GeoID(656820), 65.45, nil},
+		{FMISID(100968), 60.3267, nil},
+		{WMO(2978), 60.17523, nil},
+		{FMISID(1), 0, ErrPlaceNotFound},
+		{Place("Narnia"), 0, ErrPlaceNotFound},
+		{Place(""), 0, ErrNoPlace},
+	}
+	for _, test := range tests {
+		lat, _, err := c.coordinates(context.Background(), test.loc)
+		if !errors.Is(err, test.err) || lat != test.lat {
+			t.Errorf("coordinates(%s) = %v, %v; want %v, %v", test.loc, lat, err, test.lat, test.err)
+		}
+	}
+
+	if _, err := c.ThunderSummary(context.Background(), "Pihtipudas"); err != nil {
+		t.Errorf("ThunderSummary('Pihtipudas') returned error %v", err)
+	}
+}
+
+func TestCoordinatesWithoutElements(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<wfs:FeatureCollection timeStamp="2024-01-01T12:00:00Z" numberMatched="1" numberReturned="1"
+  xmlns:wfs="http://www.opengis.net/wfs/2.0"></wfs:FeatureCollection>`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	if _, _, err := c.coordinates(context.Background(), Place("Helsinki")); !errors.Is(err, ErrNoData) {
+		t.Errorf("coordinates() of a response without elements returned error %v; want ErrNoData", err)
+	}
+}
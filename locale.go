@@ -0,0 +1,159 @@
+package fmi
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// locale holds the texts of one output language. Scales are indexed by the
+// classes returned by windClass, compassSector, cloudClass, humidexClass
+// and windChillClass.
+type locale struct {
+	tag language.Tag
+
+	header        string
+	temperature   string
+	noTemperature string
+	feelsLike     string
+	humidity      string
+	rain          string
+	snow          string
+	pressure      string
+	visibility    string
+	lowVisibility string
+
+	// windSpeeds are the wind classes, %s is replaced with the direction
+	windSpeeds  [7]string
+	directions  [8]string
+	cloudCovers [6]string
+	humidexes   [5]string
+	windChills  [3]string
+	// pressureTendencies are indexed by pressureTendencyClass + 4
+	pressureTendencies [9]string
+
+	presentWeathers map[PresentWeather]string
+}
+
+var finnish = &locale{
+	tag:                language.Finnish,
+	header:             "Viimeisimmät säähavainnot paikassa %s: ",
+	temperature:        "lämpötila %.1f°C",
+	noTemperature:      "lämpötilatiedot puuttuvat",
+	feelsLike:          "tuntuu kuin %.1f°C",
+	humidity:           ", ilmankosteus %.f%%",
+	rain:               ", sateen määrä %.1f mm",
+	snow:               ", lumen syvyys %.f cm",
+	pressure:           ", ilmanpaine %.1f hPa",
+	visibility:         ", näkyvyys %.f km",
+	lowVisibility:      ", sumuinen alle 1 km",
+	windSpeeds:         [...]string{"tyyntä", "heikkoa %stuulta", "kohtalaista %stuulta", "navakkaa %stuulta", "kovaa %stuulta", "myrskyä", "hirmumyrskyä"},
+	directions:         [...]string{"pohjois", "koillis", "itä", "kaakkois", "etelä", "lounais", "länsi", "luoteis"},
+	cloudCovers:        [...]string{"selkeää", "melko selkeää", "puolipilvistä", "melko pilvistä", "pilvistä", "taivas ei näy"},
+	humidexes:          [...]string{"mukava", "lämmin", "kuuma", "tukala", "erittäin tukala"},
+	windChills:         [...]string{"erittäin kylmä", "paleltumisvaara", "suuri paleltumisvaara"},
+	pressureTendencies: [...]string{"paine laskee erittäin nopeasti", "paine laskee nopeasti", "paine laskee", "paine laskee hitaasti", "paine pysyy ennallaan", "paine nousee hitaasti", "paine nousee", "paine nousee nopeasti", "paine nousee erittäin nopeasti"},
+	presentWeathers:    presentWeatherFinnish,
+}
+
+var swedish = &locale{
+	tag:                language.Swedish,
+	header:             "Senaste väderobservationerna i %s: ",
+	temperature:        "temperatur %.1f°C",
+	noTemperature:      "temperaturuppgifter saknas",
+	feelsLike:          "känns som %.1f°C",
+	humidity:           ", luftfuktighet %.f%%",
+	rain:               ", nederbörd %.1f mm",
+	snow:               ", snödjup %.f cm",
+	pressure:           ", lufttryck %.1f hPa",
+	visibility:         ", sikt %.f km",
+	lowVisibility:      ", dimmigt, sikt under 1 km",
+	windSpeeds:         [...]string{"lugnt", "svag %s vind", "måttlig %s vind", "frisk %s vind", "hård %s vind", "storm", "orkan"},
+	directions:         [...]string{"nordlig", "nordostlig", "ostlig", "sydostlig", "sydlig", "sydvästlig", "västlig", "nordvästlig"},
+	cloudCovers:        [...]string{"klart", "mestadels klart", "halvklart", "mestadels mulet", "mulet", "himlen syns inte"},
+	humidexes:          [...]string{"behagligt", "varmt", "hett", "tryckande", "mycket tryckande"},
+	windChills:         [...]string{"mycket kallt", "risk för förfrysning", "stor risk för förfrysning"},
+	pressureTendencies: [...]string{"trycket sjunker mycket snabbt", "trycket sjunker snabbt", "trycket sjunker", "trycket sjunker långsamt", "trycket är oförändrat", "trycket stiger långsamt", "trycket stiger", "trycket stiger snabbt", "trycket stiger mycket snabbt"},
+	presentWeathers:    presentWeatherSwedish,
+}
+
+var english = &locale{
+	tag:                language.English,
+	header:             "Latest weather observations at %s: ",
+	temperature:        "temperature %.1f°C",
+	noTemperature:      "temperature data missing",
+	feelsLike:          "feels like %.1f°C",
+	humidity:           ", humidity %.f%%",
+	rain:               ", precipitation %.1f mm",
+	snow:               ", snow depth %.f cm",
+	pressure:           ", pressure %.1f hPa",
+	visibility:         ", visibility %.f km",
+	lowVisibility:      ", foggy, visibility below 1 km",
+	windSpeeds:         [...]string{"calm", "light %s wind", "moderate %s wind", "fresh %s wind", "strong %s wind", "storm", "hurricane"},
+	directions:         [...]string{"northerly", "northeasterly", "easterly", "southeasterly", "southerly", "southwesterly", "westerly", "northwesterly"},
+	cloudCovers:        [...]string{"clear", "mostly clear", "partly cloudy", "mostly cloudy", "overcast", "sky obscured"},
+	humidexes:          [...]string{"comfortable", "warm", "hot", "oppressive", "very oppressive"},
+	windChills:         [...]string{"very cold", "risk of frostbite", "high risk of frostbite"},
+	pressureTendencies: [...]string{"pressure falling very rapidly", "pressure falling rapidly", "pressure falling", "pressure falling slowly", "pressure steady", "pressure rising slowly", "pressure rising", "pressure rising rapidly", "pressure rising very rapidly"},
+	presentWeathers:    presentWeatherEnglish,
+}
+
+var locales = []*locale{finnish, swedish, english}
+
+var localeMatcher = language.NewMatcher([]language.Tag{language.Finnish, language.Swedish, language.English})
+
+// localeFor returns the locale best matching tag, defaulting to Finnish
+func localeFor(tag language.Tag) *locale {
+	_, i, _ := localeMatcher.Match(tag)
+	return locales[i]
+}
+
+// windSpeed returns a textual representation of wind speed s (m/s) and
+// direction d (angle)
+func (l *locale) windSpeed(s float64, d float64) string {
+	class := windClass(s)
+	if class < 0 {
+		return ""
+	}
+	text := l.windSpeeds[class]
+	if !strings.Contains(text, "%s") {
+		return text
+	}
+	if sector := compassSector(d); sector >= 0 {
+		return strings.Replace(text, "%s", l.directions[sector], 1)
+	}
+	// Without a direction, drop the placeholder and any space left around it
+	return strings.Join(strings.Fields(strings.Replace(text, "%s", "", 1)), " ")
+}
+
+// windDirection returns the name of a wind direction d (angle)
+func (l *locale) windDirection(d float64) string {
+	if sector := compassSector(d); sector >= 0 {
+		return l.directions[sector]
+	}
+	return ""
+}
+
+// cloudCover returns a textual representation of cloud cover d (1/8)
+func (l *locale) cloudCover(d float64) (string, bool) {
+	if class := cloudClass(d); class >= 0 {
+		return l.cloudCovers[class], true
+	}
+	return "", false
+}
+
+// humidexScale returns a textual classification of humidex index h
+func (l *locale) humidexScale(h float64) (string, bool) {
+	if class := humidexClass(h); class >= 0 {
+		return l.humidexes[class], true
+	}
+	return "", false
+}
+
+// windChillScale returns a textual classification of wind chill index w
+func (l *locale) windChillScale(w float64) (string, bool) {
+	if class := windChillClass(w); class >= 0 {
+		return l.windChills[class], true
+	}
+	return "", false
+}
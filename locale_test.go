@@ -0,0 +1,110 @@
+package fmi
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocaleFor(t *testing.T) {
+	var tests = []struct {
+		tag language.Tag
+		l   *locale
+	}{
+		{language.Finnish, finnish},
+		{language.MustParse("sv-FI"), swedish},
+		{language.AmericanEnglish, english},
+		{language.German, finnish},
+		{language.Und, finnish},
+	}
+	for _, test := range tests {
+		if got := localeFor(test.tag); got != test.l {
+			t.Errorf("localeFor(%s) = %s; want %s", test.tag, got.tag, test.l.tag)
+		}
+	}
+}
+
+func TestLocaleWindSpeed(t *testing.T) {
+	var tests = []struct {
+		l    *locale
+		v, d float64
+		s    string
+	}{
+		{finnish, 2, 225, "heikkoa lounaistuulta"},
+		{finnish, 2, math.NaN(), "heikkoa tuulta"},
+		{swedish, 0.5, 0, "lugnt"},
+		{swedish, 2, 225, "svag sydvästlig vind"},
+		{swedish, 10, math.NaN(), "frisk vind"},
+		{english, 2, 225, "light southwesterly wind"},
+		{english, 6, 90, "moderate easterly wind"},
+		{english, 40, 90, "hurricane"},
+		{english, -1, 90, ""},
+	}
+	for _, test := range tests {
+		got := test.l.windSpeed(test.v, test.d)
+		if got != test.s {
+			t.Errorf("%s windSpeed(%.f, %.f) = '%s'; want '%s'", test.l.tag, test.v, test.d, got, test.s)
+		}
+	}
+}
+
+func TestLocaleScales(t *testing.T) {
+	var tests = []struct {
+		l               *locale
+		cloud, humidex  string
+		windChill, wind string
+	}{
+		{swedish, "halvklart", "tryckande", "risk för förfrysning", "västlig"},
+		{english, "partly cloudy", "oppressive", "risk of frostbite", "westerly"},
+	}
+	for _, test := range tests {
+		if got, _ := test.l.cloudCover(4); got != test.cloud {
+			t.Errorf("%s cloudCover(4) = '%s'; want '%s'", test.l.tag, got, test.cloud)
+		}
+		if got, _ := test.l.humidexScale(38); got != test.humidex {
+			t.Errorf("%s humidexScale(38) = '%s'; want '%s'", test.l.tag, got, test.humidex)
+		}
+		if got, _ := test.l.windChillScale(-40); got != test.windChill {
+			t.Errorf("%s windChillScale(-40) = '%s'; want '%s'", test.l.tag, got, test.windChill)
+		}
+		if got := test.l.windDirection(270); got != test.wind {
+			t.Errorf("%s windDirection(270) = '%s'; want '%s'", test.l.tag, got, test.wind)
+		}
+	}
+}
+
+func TestFormatObservation(t *testing.T) {
+	o := Observation{
+		Temperature:   Value{18.5, true},
+		CloudCover:    Value{4, true},
+		WindSpeed:     Value{4, true},
+		WindDirection: Value{270, true},
+		Humidity:      Value{56, true},
+	}
+	var tests = []struct {
+		tag language.Tag
+		s   string
+	}{
+		{language.Finnish, "Viimeisimmät säähavainnot paikassa Turku: lämpötila 18.5°C (tuntuu kuin 16.5°C), puolipilvistä, heikkoa länsituulta 4.0 m/s, ilmankosteus 56%"},
+		{language.Swedish, "Senaste väderobservationerna i Turku: temperatur 18.5°C (känns som 16.5°C), halvklart, svag västlig vind 4.0 m/s, luftfuktighet 56%"},
+		{language.English, "Latest weather observations at Turku: temperature 18.5°C (feels like 16.5°C), partly cloudy, light westerly wind 4.0 m/s, humidity 56%"},
+	}
+	for _, test := range tests {
+		got := FormatObservation("turku", o, test.tag)
+		if got != test.s {
+			t.Errorf("got '%s', wanted '%s'", got, test.s)
+		}
+	}
+}
+
+func TestWeatherIn(t *testing.T) {
+	useTestServer(t)
+
+	s, err := defaultClient.WeatherIn(context.Background(), "Helsinki", language.English)
+	want := "Latest weather observations at Helsinki: temperature 18.5°C (feels like 16.5°C), partly cloudy, light westerly wind 4.0 m/s (6.0 m/s), humidity 56%"
+	if err != nil || s != want {
+		t.Errorf("WeatherIn('Helsinki', en) = '%s', %v; want '%s'", s, err, want)
+	}
+}
@@ -0,0 +1,78 @@
+package fmi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Location selects the stations a query returns observations for. Create
+// locations with Place, LatLon, FMISID, WMO, GeoID or BBox.
+type Location struct {
+	param string
+	value string
+}
+
+// Place selects the stations nearest to a named place, e.g. "Helsinki"
+func Place(name string) Location {
+	return Location{"place", name}
+}
+
+// LatLon selects the stations nearest to a coordinate
+func LatLon(lat, lon float64) Location {
+	return Location{"latlon", formatCoordinates(lat, lon)}
+}
+
+// FMISID selects a station by its FMI station id
+func FMISID(id int) Location {
+	return Location{"fmisid", strconv.Itoa(id)}
+}
+
+// WMO selects a station by its WMO station id
+func WMO(id int) Location {
+	return Location{"wmo", strconv.Itoa(id)}
+}
+
+// GeoID selects the stations nearest to a GeoNames place id
+func GeoID(id int) Location {
+	return Location{"geoid", strconv.Itoa(id)}
+}
+
+// BBox selects every station inside a bounding box
+func BBox(minLat, minLon, maxLat, maxLon float64) Location {
+	// FMI's bounding boxes are given as lon,lat pairs
+	return Location{"bbox", fmt.Sprintf("%s,%s,%s,%s",
+		strconv.FormatFloat(minLon, 'f', -1, 64), strconv.FormatFloat(minLat, 'f', -1, 64),
+		strconv.FormatFloat(maxLon, 'f', -1, 64), strconv.FormatFloat(maxLat, 'f', -1, 64))}
+}
+
+// String returns the location's value, e.g. the place name
+func (l Location) String() string {
+	return l.value
+}
+
+// valid reports whether the location selects anything
+func (l Location) valid() bool {
+	return l.param != "" && l.value != ""
+}
+
+// set adds the location to a WFS query
+func (l Location) set(q url.Values) {
+	q.Set(l.param, l.value)
+	switch l.param {
+	case "place", "latlon", "geoid":
+		q.Set("maxlocations", "2")
+	}
+}
+
+// station returns what the location tells about a station
+func (l Location) station(s Station) Station {
+	if l.param == "fmisid" && s.FMISID == 0 {
+		s.FMISID, _ = strconv.Atoi(l.value)
+	}
+	return s
+}
+
+func formatCoordinates(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+}
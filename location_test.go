@@ -0,0 +1,75 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLocationSet(t *testing.T) {
+	var tests = []struct {
+		loc          Location
+		param, value string
+		maxlocations string
+	}{
+		{Place("Helsinki"), "place", "Helsinki", "2"},
+		{LatLon(60.17523, 24.94459), "latlon", "60.17523,24.94459", "2"},
+		{FMISID(100971), "fmisid", "100971", ""},
+		{WMO(2978), "wmo", "2978", ""},
+		{GeoID(658225), "geoid", "658225", "2"},
+		{BBox(60, 24.5, 60.5, 25), "bbox", "24.5,60,25,60.5", ""},
+	}
+	for _, test := range tests {
+		q := url.Values{}
+		test.loc.set(q)
+		if got := q.Get(test.param); got != test.value {
+			t.Errorf("%s = '%s'; want '%s'", test.param, got, test.value)
+		}
+		if got := q.Get("maxlocations"); got != test.maxlocations {
+			t.Errorf("%s maxlocations = '%s'; want '%s'", test.param, got, test.maxlocations)
+		}
+	}
+
+	if Place("").valid() || (Location{}).valid() {
+		t.Errorf("empty locations should not be valid")
+	}
+}
+
+func TestLatestObservations(t *testing.T) {
+	// Two stations, second one reported only at the older time
+	collection := strings.Replace(testCollection, `<gml:pos>60.17523 24.94459 </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>2024-01-01T12:00:00Z</BsWfs:Time>
+      <BsWfs:ParameterName>rh</BsWfs:ParameterName>`, `<gml:pos>60.32670 24.95675 </gml:pos></gml:Point></BsWfs:Location>
+      <BsWfs:Time>2024-01-01T11:50:00Z</BsWfs:Time>
+      <BsWfs:ParameterName>rh</BsWfs:ParameterName>`, 1)
+
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(collection))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	obs, err := c.LatestObservations(context.Background(), BBox(60, 24.5, 60.5, 25))
+	if err != nil {
+		t.Fatalf("LatestObservations() returned error %v", err)
+	}
+	if query.Get("bbox") != "24.5,60,25,60.5" {
+		t.Errorf("bbox = '%s'; want '24.5,60,25,60.5'", query.Get("bbox"))
+	}
+	if len(obs) != 2 || obs[0].Station.Latitude != 60.17523 || obs[1].Station.Latitude != 60.3267 {
+		t.Fatalf("LatestObservations() = %+v; want two stations in response order", obs)
+	}
+	if !obs[0].Temperature.Valid || obs[0].Humidity.Valid || !obs[1].Humidity.Valid {
+		t.Errorf("LatestObservations() = %+v; want temperature from the first station, humidity from the second", obs)
+	}
+
+	o, err := c.ObservationsAt(context.Background(), FMISID(100971))
+	if err != nil || o.Station.FMISID != 100971 {
+		t.Errorf("ObservationsAt(FMISID(100971)) = %+v, %v; want station 100971", o.Station, err)
+	}
+}
@@ -0,0 +1,178 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Stored queries of marine observations
+const (
+	mareographQuery = "fmi::observations::mareograph::simple"
+	waveQuery       = "fmi::observations::wave::simple"
+)
+
+// marineWindow is how far back the latest marine observations are
+// searched. Wave buoys report only every half hour.
+const marineWindow = time.Hour
+
+// SeaLevel holds the sea level observed by a mareograph at one time
+type SeaLevel struct {
+	Station Station   `json:"station"`
+	Time    time.Time `json:"time"`
+
+	Level            Value `json:"level"`             // cm, relative to the theoretical mean water level
+	WaterTemperature Value `json:"water_temperature"` // degC
+}
+
+// seaLevelParameters maps the parameters of mareographs to SeaLevel fields
+var seaLevelParameters = map[string]func(*SeaLevel) *Value{
+	"WATLEV":      func(s *SeaLevel) *Value { return &s.Level },
+	"TW_PT1H_AVG": func(s *SeaLevel) *Value { return &s.WaterTemperature },
+}
+
+var seaLevelMeasures = []string{"WATLEV", "TW_PT1H_AVG"}
+
+// Waves holds the waves observed by a wave buoy at one time
+type Waves struct {
+	Station Station   `json:"station"`
+	Time    time.Time `json:"time"`
+
+	Height           Value `json:"height"`            // m, significant wave height
+	Period           Value `json:"period"`            // s, peak period
+	Direction        Value `json:"direction"`         // degrees, the waves come from
+	WaterTemperature Value `json:"water_temperature"` // degC
+}
+
+// waveParameters maps the parameters of wave buoys to Waves fields
+var waveParameters = map[string]func(*Waves) *Value{
+	"WaveHs":   func(w *Waves) *Value { return &w.Height },
+	"WTP":      func(w *Waves) *Value { return &w.Period },
+	"ModalWDi": func(w *Waves) *Value { return &w.Direction },
+	"TWATER":   func(w *Waves) *Value { return &w.WaterTemperature },
+}
+
+var waveMeasures = []string{"WaveHs", "WTP", "ModalWDi", "TWATER"}
+
+// SeaLevel returns the newest sea level and water temperature observed by
+// the mareograph nearest to a location. Each is the newest value observed,
+// so the hourly water temperature may be older than Time, the time of the
+// newest value. There is no limit on the distance, so for inland locations
+// the mareograph is on the coast.
+func (c *Client) SeaLevel(ctx context.Context, loc Location) (SeaLevel, error) {
+	r, station, err := c.fetchLatestOf(ctx, loc, mareographQuery, seaLevelMeasures, marineWindow, 10*time.Minute, SeaLevelStation, extractNewestValues)
+	if err != nil {
+		return SeaLevel{}, err
+	}
+
+	s := SeaLevel{Station: station, Time: r.Time}
+	setValues(&s, r.Values, seaLevelParameters)
+	if s.Level.Valid {
+		// Mareographs report millimetres
+		s.Level.Value /= 10
+	}
+	return s, nil
+}
+
+// Waves returns the newest waves observed by the wave buoy nearest to a
+// location. There is no limit on the distance, so for inland locations the
+// buoy is out at sea.
+func (c *Client) Waves(ctx context.Context, loc Location) (Waves, error) {
+	r, station, err := c.fetchLatestOf(ctx, loc, waveQuery, waveMeasures, marineWindow, 10*time.Minute, WaveStation, extractNewestValues)
+	if err != nil {
+		return Waves{}, err
+	}
+
+	w := Waves{Station: station, Time: r.Time}
+	setValues(&w, r.Values, waveParameters)
+	return w, nil
+}
+
+// MarineWeather returns the latest sea level and waves observed nearest to
+// a place as a written description in Finnish. The stations are named, as
+// for inland places they are far away on the coast. Waves are missing when
+// the buoys have been lifted for the winter.
+func (c *Client) MarineWeather(ctx context.Context, place string) (string, error) {
+	if place == "" {
+		return "", ErrNoPlace
+	}
+
+	level, levelErr := c.SeaLevel(ctx, Place(place))
+	waves, wavesErr := c.Waves(ctx, Place(place))
+	if levelErr != nil && wavesErr != nil {
+		if !errors.Is(levelErr, ErrNoData) && !errors.Is(levelErr, ErrPlaceNotFound) {
+			return "", levelErr
+		}
+		return "", wavesErr
+	}
+
+	return formatMarine(place, level, waves), nil
+}
+
+// fromDirections names the compass sectors returned by compassSector as
+// the direction something comes from
+var fromDirections = [...]string{"pohjoisesta", "koillisesta", "idästä", "kaakosta", "etelästä", "lounaasta", "lännestä", "luoteesta"}
+
+// formatMarine returns a string representation of the sea level and waves
+// at a place in Finnish. Invalid values are left out.
+func formatMarine(place string, level SeaLevel, waves Waves) string {
+	c := cases.Title(language.Finnish)
+
+	parts := make([]string, 0, 2)
+	waterTemperature := false
+
+	levelParts := make([]string, 0, 2)
+	if level.Level.Valid {
+		levelParts = append(levelParts, fmt.Sprintf("merivesi %+.f cm teoreettisesta keskivedestä", roundLevel(level.Level.Value)))
+	}
+	if level.WaterTemperature.Valid {
+		levelParts = append(levelParts, fmt.Sprintf("veden lämpötila %.1f°C", level.WaterTemperature.Value))
+		waterTemperature = true
+	}
+	if len(levelParts) > 0 {
+		parts = append(parts, strings.Join(levelParts, ", ")+formatStationName(level.Station))
+	}
+
+	waveParts := make([]string, 0, 3)
+	if waves.Height.Valid {
+		height := fmt.Sprintf("aallonkorkeus %.1f m", waves.Height.Value)
+		if sector := compassSector(waves.Direction.Value); waves.Direction.Valid && sector >= 0 {
+			height += " " + fromDirections[sector]
+		}
+		waveParts = append(waveParts, height)
+	}
+	if waves.Period.Valid {
+		waveParts = append(waveParts, fmt.Sprintf("aallon jakso %.1f s", waves.Period.Value))
+	}
+	if waves.WaterTemperature.Valid && !waterTemperature {
+		waveParts = append(waveParts, fmt.Sprintf("veden lämpötila %.1f°C", waves.WaterTemperature.Value))
+	}
+	if len(waveParts) > 0 {
+		parts = append(parts, strings.Join(waveParts, ", ")+formatStationName(waves.Station))
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, "meritiedot puuttuvat")
+	}
+	return fmt.Sprintf("Merisää paikassa %s: %s", c.String(strings.ToLower(place)), strings.Join(parts, ", "))
+}
+
+// formatStationName returns the name of a station in parentheses, or an
+// empty string if the name is unknown
+func formatStationName(s Station) string {
+	if s.Name == "" {
+		return ""
+	}
+	return " (" + s.Name + ")"
+}
+
+// roundLevel rounds a sea level to whole centimetres, avoiding "-0"
+func roundLevel(cm float64) float64 {
+	return math.Round(cm) + 0
+}
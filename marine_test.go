@@ -0,0 +1,86 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestFormatMarine(t *testing.T) {
+	hanko := Station{Name: "Hanko Pikku Kolalahti"}
+	buoy := Station{Name: "Suomenlahden aaltopoiju"}
+	var tests = []struct {
+		level SeaLevel
+		waves Waves
+		s     string
+	}{
+		{
+			SeaLevel{Station: hanko, Level: Value{23.4, true}},
+			Waves{Height: Value{0.8, true}},
+			"Merisää paikassa Hanko: merivesi +23 cm teoreettisesta keskivedestä (Hanko Pikku Kolalahti), aallonkorkeus 0.8 m",
+		},
+		{
+			SeaLevel{Level: Value{-0.3, true}, WaterTemperature: Value{14.2, true}},
+			Waves{Station: buoy, Height: Value{1.25, true}, Direction: Value{225, true}, Period: Value{4.5, true}, WaterTemperature: Value{13, true}},
+			"Merisää paikassa Hanko: merivesi +0 cm teoreettisesta keskivedestä, veden lämpötila 14.2°C, aallonkorkeus 1.2 m lounaasta, aallon jakso 4.5 s (Suomenlahden aaltopoiju)",
+		},
+		{
+			SeaLevel{Level: Value{-31, true}},
+			Waves{WaterTemperature: Value{3.5, true}},
+			"Merisää paikassa Hanko: merivesi -31 cm teoreettisesta keskivedestä, veden lämpötila 3.5°C",
+		},
+		{SeaLevel{}, Waves{}, "Merisää paikassa Hanko: meritiedot puuttuvat"},
+	}
+	for _, test := range tests {
+		if got := formatMarine("hanko", test.level, test.waves); got != test.s {
+			t.Errorf("formatMarine(%v, %v) = '%s'; want '%s'", test.level, test.waves, got, test.s)
+		}
+	}
+}
+
+func TestClientMarine(t *testing.T) {
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Hanko Pikku Kolalahti", FMISID: 134253, Latitude: 59.82287, Longitude: 22.97658, Networks: []string{"Mareografi"},
+			Values: map[string]float64{"WATLEV": 234, "TW_PT1H_AVG": 14.2}},
+		fmitest.Station{Name: "Suomenlahden aaltopoiju", FMISID: 134220, Latitude: 59.9650, Longitude: 25.2350, Networks: []string{"Aaltopoiju"},
+			Values: map[string]float64{"WaveHs": 0.8, "WTP": 4.5, "ModalWDi": 200, "TWATER": 13.1}},
+	)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	level, err := c.SeaLevel(ctx, FMISID(134253))
+	if err != nil {
+		t.Fatalf("SeaLevel() returned error %v", err)
+	}
+	if level.Level != (Value{23.4, true}) || level.WaterTemperature != (Value{14.2, true}) || level.Station.Name != "Hanko Pikku Kolalahti" {
+		t.Errorf("SeaLevel() = %+v; want level 23.4 cm and water 14.2°C at Hanko Pikku Kolalahti", level)
+	}
+	if d := time.Since(level.Time); d < 0 || d > marineWindow+10*time.Minute {
+		t.Errorf("SeaLevel().Time = %v; want a time within the last hour", level.Time)
+	}
+
+	waves, err := c.Waves(ctx, FMISID(134220))
+	if err != nil {
+		t.Fatalf("Waves() returned error %v", err)
+	}
+	if waves.Height != (Value{0.8, true}) || waves.Period != (Value{4.5, true}) || waves.Station.Name != "Suomenlahden aaltopoiju" {
+		t.Errorf("Waves() = %+v; want height 0.8 m and period 4.5 s at Suomenlahden aaltopoiju", waves)
+	}
+
+	s, err := c.MarineWeather(ctx, "Hanko")
+	if err != nil {
+		t.Fatalf("MarineWeather('Hanko') returned error %v", err)
+	}
+	want := "Merisää paikassa Hanko: merivesi +23 cm teoreettisesta keskivedestä, veden lämpötila 14.2°C (Hanko Pikku Kolalahti), aallonkorkeus 0.8 m etelästä, aallon jakso 4.5 s (Suomenlahden aaltopoiju)"
+	if s != want {
+		t.Errorf("MarineWeather('Hanko') = '%s'; want '%s'", s, want)
+	}
+
+	if _, err := c.MarineWeather(ctx, "Kuopio"); !errors.Is(err, ErrPlaceNotFound) {
+		t.Errorf("MarineWeather('Kuopio') returned error %v; want ErrPlaceNotFound", err)
+	}
+}
@@ -70,3 +70,27 @@ func FeelsLike(t float64, v float64, rh float64, rad float64) float64 {
 
 	return feels
 }
+
+// earthRadius is the mean radius of the Earth in km
+const earthRadius = 6371.0
+
+// Distance calculates the great-circle distance in km between two
+// coordinates using the haversine formula.
+// For reference see, https://en.wikipedia.org/wiki/Haversine_formula
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi, dLambda := phi2-phi1, (lon2-lon1)*math.Pi/180
+	a := math.Pow(math.Sin(dPhi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(dLambda/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Bearing calculates the initial bearing in degrees (0-360, clockwise from
+// north) of the great circle from the first coordinate to the second.
+// For reference see, https://www.movable-type.co.uk/scripts/latlong.html
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
@@ -97,3 +97,36 @@ func TestFeelsLike(t *testing.T) {
 		}
 	}
 }
+
+func TestDistance(t *testing.T) {
+	var tests = []struct {
+		lat1, lon1, lat2, lon2, d float64
+	}{
+		{60.17523, 24.94459, 60.17523, 24.94459, 0},
+		{60.17523, 24.94459, 60.32670, 24.95675, 16.856},
+		{60.17523, 24.94459, 60.45, 22.27, 150.417},
+	}
+	for _, test := range tests {
+		got := Distance(test.lat1, test.lon1, test.lat2, test.lon2)
+		if !cmp.Equal(got, test.d, cmpopts.EquateApprox(0, 0.001)) {
+			t.Errorf("Distance(%f, %f, %f, %f) = %f; want %f", test.lat1, test.lon1, test.lat2, test.lon2, got, test.d)
+		}
+	}
+}
+
+func TestBearing(t *testing.T) {
+	var tests = []struct {
+		lat1, lon1, lat2, lon2, b float64
+	}{
+		{60, 25, 61, 25, 0},
+		{60, 25, 60, 26, 89.567},
+		{60, 25, 59, 25, 180},
+		{60.17523, 24.94459, 60.45, 22.27, 282.878},
+	}
+	for _, test := range tests {
+		got := Bearing(test.lat1, test.lon1, test.lat2, test.lon2)
+		if !cmp.Equal(got, test.b, cmpopts.EquateApprox(0, 0.001)) {
+			t.Errorf("Bearing(%f, %f, %f, %f) = %f; want %f", test.lat1, test.lon1, test.lat2, test.lon2, got, test.b)
+		}
+	}
+}
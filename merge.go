@@ -0,0 +1,121 @@
+package fmi
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// mergeLocations is the number of stations fetched for a place or
+// coordinate when merging
+const mergeLocations = 5
+
+// sourceDistance is the distance in km within which a catalog station is
+// taken to be the station of an observation
+const sourceDistance = 1.0
+
+// mergeRows fills the measures missing from r with the values of the
+// nearest other rows observed at the same time within distance km of r's
+// station. The stations the values came from are recorded in the Sources
+// of the returned row, and the quality flags of the values they replace
+// are cleared.
+func mergeRows(r row, others []row, distance float64) row {
+	station := parseStation(r.Location)
+	distances := make(map[string]float64, len(others))
+	candidates := make([]row, 0, len(others))
+	for _, other := range others {
+		s := parseStation(other.Location)
+		d := Distance(station.Latitude, station.Longitude, s.Latitude, s.Longitude)
+		if other.Location == r.Location || !other.Time.Equal(r.Time) || d > distance {
+			continue
+		}
+		distances[other.Location] = d
+		candidates = append(candidates, other)
+	}
+	slices.SortStableFunc(candidates, func(a, b row) int {
+		return cmp.Compare(distances[a.Location], distances[b.Location])
+	})
+
+	merged := row{Location: r.Location, Time: r.Time, Values: maps.Clone(r.Values), Sources: maps.Clone(r.Sources), Quality: maps.Clone(r.Quality)}
+	for _, measure := range measures {
+		if v, ok := merged.Values[measure]; ok && !math.IsNaN(v) {
+			continue
+		}
+		for _, other := range candidates {
+			if v, ok := other.Values[measure]; ok && !math.IsNaN(v) {
+				if merged.Sources == nil {
+					merged.Sources = make(map[string]Station)
+				}
+				merged.Values[measure] = v
+				merged.Sources[measure] = parseStation(other.Location)
+				delete(merged.Quality, measure)
+				break
+			}
+		}
+	}
+	if len(merged.Quality) == 0 {
+		merged.Quality = nil
+	}
+	return merged
+}
+
+// nameSources fills in the source stations' names and ids from the
+// stations named by the response, or from the station catalog if it has
+// already been fetched
+func (c *Client) nameSources(stations map[string]Station, sources map[string]Station) {
+	for measure, s := range sources {
+		for location, station := range stations {
+			if l := parseStation(location); l.Latitude == s.Latitude && l.Longitude == s.Longitude {
+				s = station
+				break
+			}
+		}
+		if s.Name == "" {
+			if catalog := c.cachedStations(); catalog != nil {
+				s = identifyStation(catalog, s, AnyStation)
+			}
+		}
+		sources[measure] = s
+	}
+}
+
+// identifyStation returns the catalog station of type t within
+// sourceDistance of s, or s if there is none
+func identifyStation(catalog *Catalog, s Station, t StationType) Station {
+	nearest := catalog.Nearest(s.Latitude, s.Longitude, 1, t)
+	if len(nearest) == 1 && Distance(s.Latitude, s.Longitude, nearest[0].Latitude, nearest[0].Longitude) <= sourceDistance {
+		return nearest[0]
+	}
+	return s
+}
+
+// sourceNames returns the names of source stations keyed by parameter.
+// Stations without a name are named by their coordinates.
+func sourceNames(sources map[string]Station) map[string]string {
+	names := make(map[string]string, len(sources))
+	for measure, s := range sources {
+		if s.Name != "" {
+			names[measure] = s.Name
+		} else {
+			names[measure] = formatCoordinates(s.Latitude, s.Longitude)
+		}
+	}
+	return names
+}
+
+// formatSources returns the names of the stations the parameters came from
+// in parentheses, or an empty string if they came from the main station
+func formatSources(sources map[string]string, parameters ...string) string {
+	names := make([]string, 0)
+	for _, p := range parameters {
+		if name, ok := sources[p]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
@@ -0,0 +1,141 @@
+package fmi
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kari/fmi/fmitest"
+	"golang.org/x/text/language"
+)
+
+func TestMergeRows(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := row{Location: "60.17523 24.94459 ", Time: ts, Values: observations{"t2m": 1.5, "snow_aws": math.NaN(), "n_man": math.NaN()}}
+	others := []row{
+		{Location: "60.17523 24.94459 ", Time: ts, Values: r.Values},
+		{Location: "61.46561 23.74726 ", Time: ts, Values: observations{"snow_aws": 30, "n_man": 8}},
+		{Location: "60.3267 24.95675 ", Time: ts, Values: observations{"snow_aws": 12, "n_man": math.NaN()}},
+	}
+
+	got := mergeRows(r, others, 50)
+	want := row{
+		Location: r.Location,
+		Time:     ts,
+		Values:   observations{"t2m": 1.5, "snow_aws": 12, "n_man": math.NaN()},
+		Sources:  map[string]Station{"snow_aws": {Latitude: 60.3267, Longitude: 24.95675}},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b float64) bool { return a == b || math.IsNaN(a) && math.IsNaN(b) })); diff != "" {
+		t.Errorf("mergeRows() mismatch (-want +got):\n%s", diff)
+	}
+	if !math.IsNaN(r.Values["snow_aws"]) {
+		t.Errorf("mergeRows() should not modify the merged row")
+	}
+
+	if got := mergeRows(r, others, 200); got.Values["n_man"] != 8 {
+		t.Errorf("mergeRows() within 200 km should fill n_man from Tampere, instead got %v", got.Values)
+	}
+
+	rejected := row{Location: r.Location, Time: ts, Values: observations{"t2m": 1.5, "snow_aws": math.NaN()}, Quality: map[string]QualityFlag{"snow_aws": QualityOutOfRange}}
+	if got := mergeRows(rejected, others, 50); got.Values["snow_aws"] != 12 || got.Quality != nil {
+		t.Errorf("mergeRows() replacing a rejected value = %v, quality %v; want snow_aws 12 without flags", got.Values, got.Quality)
+	}
+	if rejected.Quality["snow_aws"] != QualityOutOfRange {
+		t.Errorf("mergeRows() should not modify the quality flags of the merged row")
+	}
+
+	earlier := []row{{Location: "60.3267 24.95675 ", Time: ts.Add(-10 * time.Minute), Values: observations{"snow_aws": 12}}}
+	if got := mergeRows(r, earlier, 50); !math.IsNaN(got.Values["snow_aws"]) {
+		t.Errorf("mergeRows() should not fill values from another time, instead got %v", got.Values)
+	}
+}
+
+func TestFormatSources(t *testing.T) {
+	sources := map[string]string{"ws_10min": "Helsinki-Vantaa", "wg_10min": "Helsinki-Vantaa", "wd_10min": "Harmaja"}
+	var tests = []struct {
+		parameters []string
+		s          string
+	}{
+		{[]string{"t2m"}, ""},
+		{[]string{"ws_10min", "wg_10min"}, " (Helsinki-Vantaa)"},
+		{[]string{"ws_10min", "wd_10min", "wg_10min"}, " (Helsinki-Vantaa, Harmaja)"},
+	}
+	for _, test := range tests {
+		if got := formatSources(sources, test.parameters...); got != test.s {
+			t.Errorf("formatSources(%v) = '%s'; want '%s'", test.parameters, got, test.s)
+		}
+	}
+}
+
+func TestClientMerge(t *testing.T) {
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Helsinki Kaisaniemi", FMISID: 100971, Latitude: 60.17523, Longitude: 24.94459, Values: map[string]float64{"t2m": 1.5, "rh": 80}},
+		fmitest.Station{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, Latitude: 60.3267, Longitude: 24.95675, Values: map[string]float64{"t2m": 0.5, "snow_aws": 12}},
+		fmitest.Station{Name: "Tampere Härmälä", FMISID: 101124, Latitude: 61.46561, Longitude: 23.74726, Values: map[string]float64{"n_man": 8}},
+	)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithMergeDistance(30))
+	s, err := c.Weather(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Weather('Helsinki') returned error %v", err)
+	}
+	if want := "lämpötila 1.5°C"; !strings.Contains(s, want) {
+		t.Errorf("Weather('Helsinki') = '%s'; should contain '%s'", s, want)
+	}
+	if want := "lumen syvyys 12 cm (Helsinki-Vantaa lentoasema)"; !strings.Contains(s, want) {
+		t.Errorf("Weather('Helsinki') = '%s'; should contain '%s'", s, want)
+	}
+	if strings.Contains(s, "pilvistä") {
+		t.Errorf("Weather('Helsinki') = '%s'; should not merge cloud cover from Tampere", s)
+	}
+
+	o, err := c.Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations('Helsinki') returned error %v", err)
+	}
+	if o.SnowDepth != (Value{12, true}) || o.Sources["snow_aws"].FMISID != 100968 {
+		t.Errorf("Observations('Helsinki') = %v, sources %v; want snow depth 12 from 100968", o.SnowDepth, o.Sources)
+	}
+	if want := "lumen syvyys 12 cm (Helsinki-Vantaa lentoasema)"; !strings.Contains(FormatObservation("Helsinki", o, language.Finnish), want) {
+		t.Errorf("FormatObservation() should contain '%s'", want)
+	}
+
+	s, _ = NewClient(WithBaseURL(srv.URL)).Weather(context.Background(), "Helsinki")
+	if strings.Contains(s, "lumen syvyys") {
+		t.Errorf("Weather('Helsinki') without merging = '%s'; should not contain snow depth", s)
+	}
+}
+
+func TestClientMergeQuality(t *testing.T) {
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Helsinki Kaisaniemi", FMISID: 100971, Latitude: 60.17523, Longitude: 24.94459, Values: map[string]float64{"t2m": 1.5, "rh": 150, "vis": -5}},
+		fmitest.Station{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, Latitude: 60.3267, Longitude: 24.95675, Values: map[string]float64{"t2m": 0.5, "rh": 85}},
+	)
+	defer srv.Close()
+
+	// Without merging, the rejected humidity is left invalid and flagged
+	o, err := NewClient(WithBaseURL(srv.URL)).ObservationsAt(context.Background(), FMISID(100971))
+	if err != nil {
+		t.Fatalf("ObservationsAt(fmisid 100971) returned error %v", err)
+	}
+	if o.Humidity.Valid || o.Quality["rh"] != QualityOutOfRange {
+		t.Errorf("ObservationsAt(fmisid 100971) without merging = %v, quality %v; want invalid humidity flagged out of range", o.Humidity, o.Quality)
+	}
+
+	// Merging replaces it, so only the visibility nobody reported stays
+	// flagged
+	o, err = NewClient(WithBaseURL(srv.URL), WithMergeDistance(30)).Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations('Helsinki') returned error %v", err)
+	}
+	if o.Humidity != (Value{85, true}) || o.Sources["rh"].FMISID != 100968 {
+		t.Errorf("Observations('Helsinki') = %v, sources %v; want humidity 85 from 100968", o.Humidity, o.Sources)
+	}
+	if _, ok := o.Quality["rh"]; ok || o.Quality["vis"] != QualityOutOfRange || o.Visibility.Valid {
+		t.Errorf("Observations('Helsinki') quality = %v; want only vis flagged", o.Quality)
+	}
+}
@@ -0,0 +1,162 @@
+package fmi
+
+import (
+	"maps"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value is a measured value. Valid is false when the station did not
+// report the value. In JSON, missing values are null.
+type Value struct {
+	Value float64
+	Valid bool
+}
+
+// MarshalJSON encodes the value as a number, or null if it is missing
+func (v Value) MarshalJSON() ([]byte, error) {
+	if !v.Valid || math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+		return []byte("null"), nil
+	}
+	return strconv.AppendFloat(nil, v.Value, 'f', -1, 64), nil
+}
+
+// UnmarshalJSON decodes a number, or null as a missing value
+func (v *Value) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = Value{}
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*v = Value{Value: f, Valid: true}
+	return nil
+}
+
+// Station identifies an observation station. The simple response format
+// only carries coordinates, so Name and FMISID are empty when unknown. WMO
+// and Type are only known for stations of a Catalog.
+type Station struct {
+	Name      string      `json:"name,omitempty"`
+	FMISID    int         `json:"fmisid,omitempty"`
+	WMO       int         `json:"wmo,omitempty"`
+	Type      StationType `json:"-"`
+	Latitude  float64     `json:"latitude"`
+	Longitude float64     `json:"longitude"`
+}
+
+// Observation holds the weather observed at a station at one time
+type Observation struct {
+	Station Station   `json:"station"`
+	Time    time.Time `json:"time"`
+
+	Temperature            Value `json:"temperature"`             // degC
+	WindSpeed              Value `json:"wind_speed"`              // m/s, 10 min average
+	WindGust               Value `json:"wind_gust"`               // m/s
+	WindDirection          Value `json:"wind_direction"`          // degrees
+	Humidity               Value `json:"humidity"`                // %
+	DewPoint               Value `json:"dew_point"`               // degC
+	Precipitation          Value `json:"precipitation"`           // mm during the last hour
+	PrecipitationIntensity Value `json:"precipitation_intensity"` // mm/h
+	SnowDepth              Value `json:"snow_depth"`              // cm, -1 = no snow, 0 = snow in vicinity
+	CloudCover             Value `json:"cloud_cover"`             // 1/8, 9 = sky not visible
+	Radiation              Value `json:"radiation"`               // W/m2, global radiation
+	PresentWeather         Value `json:"present_weather"`         // WMO 4680 code, see PresentWeatherCode
+	Pressure               Value `json:"pressure"`                // hPa, at sea level
+	PressureTendency       Value `json:"pressure_tendency"`       // hPa, change during the last three hours, see WithPressureTendency
+	Visibility             Value `json:"visibility"`              // m
+
+	// Sources holds the stations of values filled from other stations than
+	// Station, keyed by FMI parameter name, e.g. "snow_aws". See
+	// WithMergeDistance.
+	Sources map[string]Station `json:"sources,omitempty"`
+
+	// Quality holds the flags of values Station reported but quality
+	// control rejected, keyed by FMI parameter name. Rejected values are
+	// left invalid, unless another station's value replaces them when
+	// merging. The value's station is then in Sources and its flag is
+	// cleared. See WithQualityLimits.
+	Quality map[string]QualityFlag `json:"quality,omitempty"`
+}
+
+// parameters maps FMI's parameter names to Observation fields
+var parameters = map[string]func(*Observation) *Value{
+	"t2m":      func(o *Observation) *Value { return &o.Temperature },
+	"ws_10min": func(o *Observation) *Value { return &o.WindSpeed },
+	"wg_10min": func(o *Observation) *Value { return &o.WindGust },
+	"wd_10min": func(o *Observation) *Value { return &o.WindDirection },
+	"rh":       func(o *Observation) *Value { return &o.Humidity },
+	"td":       func(o *Observation) *Value { return &o.DewPoint },
+	"r_1h":     func(o *Observation) *Value { return &o.Precipitation },
+	"ri_10min": func(o *Observation) *Value { return &o.PrecipitationIntensity },
+	"snow_aws": func(o *Observation) *Value { return &o.SnowDepth },
+	"n_man":    func(o *Observation) *Value { return &o.CloudCover },
+	"glob_u":   func(o *Observation) *Value { return &o.Radiation },
+	"wawa":     func(o *Observation) *Value { return &o.PresentWeather },
+	"p_sea":    func(o *Observation) *Value { return &o.Pressure },
+	"vis":      func(o *Observation) *Value { return &o.Visibility },
+
+	pressureTendency: func(o *Observation) *Value { return &o.PressureTendency },
+}
+
+// newObservation converts a row of measurements to an Observation.
+// NaN values and parameters missing from the row are left invalid.
+func newObservation(r row) Observation {
+	return convertRow(r, parameters)
+}
+
+// convertRow converts a row to an Observation using a parameter table
+func convertRow(r row, parameters map[string]func(*Observation) *Value) Observation {
+	o := Observation{
+		Station: parseStation(r.Location),
+		Time:    r.Time,
+	}
+	if len(r.Sources) > 0 {
+		o.Sources = maps.Clone(r.Sources)
+	}
+	if len(r.Quality) > 0 {
+		o.Quality = maps.Clone(r.Quality)
+	}
+	setValues(&o, r.Values, parameters)
+	return o
+}
+
+// setValues sets the fields of dst from values using a parameter table.
+// NaN values and parameters missing from the table are skipped.
+func setValues[T any](dst *T, values observations, parameters map[string]func(*T) *Value) {
+	for name, value := range values {
+		field, ok := parameters[name]
+		if !ok || math.IsNaN(value) {
+			continue
+		}
+		*field(dst) = Value{Value: value, Valid: true}
+	}
+}
+
+// measures converts an Observation back to a map of valid measurements
+func (o Observation) measures() observations {
+	obs := make(observations)
+	for name, field := range parameters {
+		if v := field(&o); v.Valid {
+			obs[name] = v.Value
+		}
+	}
+	return obs
+}
+
+// parseStation parses station coordinates from a GML position
+// ("lat lon")
+func parseStation(pos string) Station {
+	var s Station
+	fields := strings.Fields(pos)
+	if len(fields) != 2 {
+		return s
+	}
+	s.Latitude, _ = strconv.ParseFloat(fields[0], 64)
+	s.Longitude, _ = strconv.ParseFloat(fields[1], 64)
+	return s
+}
@@ -0,0 +1,229 @@
+package fmi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestNewObservation(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := row{
+		Location: "60.17523 24.94459 ",
+		Time:     ts,
+		Values:   observations{"t2m": -2.5, "rh": math.NaN(), "snow_aws": 0, "unknown": 1},
+	}
+	want := Observation{
+		Station:     Station{Latitude: 60.17523, Longitude: 24.94459},
+		Time:        ts,
+		Temperature: Value{Value: -2.5, Valid: true},
+		SnowDepth:   Value{Value: 0, Valid: true},
+	}
+
+	got := newObservation(r)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newObservation() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(observations{"t2m": -2.5, "snow_aws": 0}, got.measures()); diff != "" {
+		t.Errorf("measures() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValueJSON(t *testing.T) {
+	o := Observation{
+		Station:     Station{Name: "Turku", Latitude: 60.45, Longitude: 22.27},
+		Time:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Temperature: Value{Value: -2.5, Valid: true},
+		SnowDepth:   Value{Value: math.NaN(), Valid: true},
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() returned error %v", err)
+	}
+	if fields["temperature"] != -2.5 || fields["snow_depth"] != nil || fields["time"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("json.Marshal() = %s; want temperature -2.5, snow_depth null", data)
+	}
+
+	var got Observation
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() returned error %v", err)
+	}
+	o.SnowDepth = Value{}
+	if diff := cmp.Diff(o, got); diff != "" {
+		t.Errorf("JSON round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseStation(t *testing.T) {
+	var tests = []struct {
+		pos string
+		s   Station
+	}{
+		{"60.17523 24.94459 ", Station{Latitude: 60.17523, Longitude: 24.94459}},
+		{"", Station{}},
+		{"60.17523", Station{}},
+	}
+	for _, test := range tests {
+		got := parseStation(test.pos)
+		if got != test.s {
+			t.Errorf("parseStation('%s') = %v; want %v", test.pos, got, test.s)
+		}
+	}
+}
+
+func TestExtractLatestObservations(t *testing.T) {
+	older := time.Date(2024, 1, 1, 11, 50, 0, 0, time.UTC)
+	newer := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	measures := []string{"t2m", "rh"}
+	collection := simpleFeatureCollection{Elements: []observation{
+		{Location: "a", Time: older, Parameter: "t2m", Value: 1},
+		{Location: "a", Time: older, Parameter: "rh", Value: 50},
+		{Location: "a", Time: newer, Parameter: "t2m", Value: 2},
+		{Location: "a", Time: newer, Parameter: "rh", Value: math.NaN()},
+		{Location: "b", Time: newer, Parameter: "t2m", Value: 3},
+		{Location: "b", Time: newer, Parameter: "rh", Value: 60},
+	}}
+
+	got, ok := extractLatestObservations(collection, measures)
+	if !ok || got.Location != "b" || !got.Time.Equal(newer) {
+		t.Errorf("extractLatestObservations() = %v, %t; want station b at %v", got, ok, newer)
+	}
+
+	empty := simpleFeatureCollection{Elements: []observation{
+		{Location: "a", Time: newer, Parameter: "t2m", Value: math.NaN()},
+		{Location: "a", Time: newer, Parameter: "rh", Value: math.NaN()},
+	}}
+	if got, ok := extractLatestObservations(empty, measures); ok {
+		t.Errorf("extractLatestObservations() = %v; want no observations", got)
+	}
+}
+
+func TestExtractNewestValues(t *testing.T) {
+	older := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 1, 1, 11, 50, 0, 0, time.UTC)
+	measures := []string{"WATLEV", "TW_PT1H_AVG"}
+	collection := simpleFeatureCollection{Elements: []observation{
+		{Location: "a", Time: newer, Parameter: "WATLEV", Value: math.NaN()},
+		{Location: "b", Time: older, Parameter: "WATLEV", Value: 120},
+		{Location: "b", Time: older, Parameter: "TW_PT1H_AVG", Value: 4.5},
+		{Location: "b", Time: newer, Parameter: "WATLEV", Value: 150},
+		{Location: "b", Time: newer, Parameter: "TW_PT1H_AVG", Value: math.NaN()},
+		{Location: "c", Time: newer, Parameter: "WATLEV", Value: 90},
+		{Location: "c", Time: newer, Parameter: "TW_PT1H_AVG", Value: 5},
+	}}
+
+	got, ok := extractNewestValues(collection, measures)
+	want := row{Location: "b", Time: newer, Values: observations{"WATLEV": 150, "TW_PT1H_AVG": 4.5}}
+	if diff := cmp.Diff(want, got); !ok || diff != "" {
+		t.Errorf("extractNewestValues() mismatch (-want +got):\n%s", diff)
+	}
+	if got, ok := extractNewestValues(simpleFeatureCollection{}, measures); ok {
+		t.Errorf("extractNewestValues() = %v; want no observations", got)
+	}
+}
+
+func TestExtractNearestObservations(t *testing.T) {
+	older := time.Date(2024, 1, 1, 11, 50, 0, 0, time.UTC)
+	newer := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	measures := []string{"t2m", "rh"}
+	collection := simpleFeatureCollection{Elements: []observation{
+		{Location: "a", Time: older, Parameter: "t2m", Value: math.NaN()},
+		{Location: "a", Time: older, Parameter: "rh", Value: math.NaN()},
+		{Location: "b", Time: older, Parameter: "t2m", Value: 1},
+		{Location: "b", Time: older, Parameter: "rh", Value: 50},
+		{Location: "b", Time: newer, Parameter: "t2m", Value: 2},
+		{Location: "b", Time: newer, Parameter: "rh", Value: math.NaN()},
+		{Location: "c", Time: newer, Parameter: "t2m", Value: 3},
+		{Location: "c", Time: newer, Parameter: "rh", Value: 60},
+	}}
+
+	got, ok := extractNearestObservations(collection, measures)
+	if !ok || got.Location != "b" || !got.Time.Equal(newer) {
+		t.Errorf("extractNearestObservations() = %v, %t; want station b at %v", got, ok, newer)
+	}
+	if got, ok := extractNearestObservations(simpleFeatureCollection{}, measures); ok {
+		t.Errorf("extractNearestObservations() = %v; want no observations", got)
+	}
+}
+
+func TestClientObservations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testCollection))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	obs, err := c.Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations('Helsinki') returned error %v", err)
+	}
+	if obs.Temperature != (Value{-2.5, true}) || obs.Humidity != (Value{80, true}) || obs.WindSpeed.Valid {
+		t.Errorf("Observations('Helsinki') = %+v; want temperature -2.5, humidity 80, no wind", obs)
+	}
+	if obs.Station.Latitude != 60.17523 || obs.Station.Longitude != 24.94459 {
+		t.Errorf("Observations('Helsinki') station = %+v; want 60.17523, 24.94459", obs.Station)
+	}
+
+	if _, err := c.Observations(context.Background(), ""); err == nil {
+		t.Errorf("Observations('') should return an error")
+	}
+}
+
+func TestObservationsStation(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+
+	for _, format := range []ResponseFormat{AutoFormat, MultiPointCoverageFormat} {
+		c := NewClient(WithBaseURL(srv.URL), WithResponseFormat(format))
+		n := len(srv.Requests())
+		obs, err := c.Observations(context.Background(), "Helsinki")
+		if err != nil {
+			t.Fatalf("%s: Observations('Helsinki') returned error %v", format, err)
+		}
+		if obs.Station.Name != "Helsinki Kaisaniemi" || obs.Station.FMISID != 100971 {
+			t.Errorf("%s: Observations('Helsinki').Station = %+v; want Helsinki Kaisaniemi 100971", format, obs.Station)
+		}
+		if got := len(srv.Requests()) - n; got != 1 {
+			t.Errorf("%s: Observations('Helsinki') made %d requests; want 1", format, got)
+		}
+
+		all, err := c.LatestObservations(context.Background(), BBox(60, 24.5, 60.5, 25))
+		if err != nil {
+			t.Fatalf("%s: LatestObservations() returned error %v", format, err)
+		}
+		for _, o := range all {
+			if o.Station.Name == "" || o.Station.FMISID == 0 {
+				t.Errorf("%s: LatestObservations() station = %+v; want a named station", format, o.Station)
+			}
+		}
+	}
+
+	// The simple format does not name stations, so they are named from the
+	// catalog only once it has been fetched
+	c := NewClient(WithBaseURL(srv.URL), WithResponseFormat(SimpleFormat))
+	n := len(srv.Requests())
+	obs, err := c.Observations(context.Background(), "Helsinki")
+	if err != nil || obs.Station.Name != "" || len(srv.Requests())-n != 1 {
+		t.Errorf("simple: Observations('Helsinki') = %+v, %v with %d requests; want an unnamed station with 1 request", obs.Station, err, len(srv.Requests())-n)
+	}
+	if _, err := c.Stations(context.Background()); err != nil {
+		t.Fatalf("Stations() returned error %v", err)
+	}
+	obs, err = c.Observations(context.Background(), "Helsinki")
+	if err != nil || obs.Station.Name != "Helsinki Kaisaniemi" {
+		t.Errorf("simple: Observations('Helsinki') after Stations() = %+v, %v; want Helsinki Kaisaniemi", obs.Station, err)
+	}
+}
@@ -0,0 +1,312 @@
+package fmi
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/text/language"
+)
+
+// PresentWeather is a present weather code reported by automatic weather
+// stations (wawa), as defined in WMO code table 4680.
+// For reference, see:
+// https://www.wmo.int/pages/prog/www/WMOCodes/WMO306_vI1/Publications/2017update/Sel9.pdf
+type PresentWeather int
+
+// Main present weather codes, the codes in between refine these
+const (
+	PresentWeatherNoSignificant PresentWeather = 0
+	PresentWeatherHaze          PresentWeather = 4
+	PresentWeatherMist          PresentWeather = 10
+	PresentWeatherRecent        PresentWeather = 20 // weather during the preceding hour
+	PresentWeatherFog           PresentWeather = 30
+	PresentWeatherPrecipitation PresentWeather = 40
+	PresentWeatherDrizzle       PresentWeather = 50
+	PresentWeatherRain          PresentWeather = 60
+	PresentWeatherSnow          PresentWeather = 70
+	PresentWeatherShowers       PresentWeather = 80
+	PresentWeatherThunderstorm  PresentWeather = 90
+	PresentWeatherTornado       PresentWeather = 99
+)
+
+// String returns the Finnish description of the code
+func (w PresentWeather) String() string {
+	return w.Text(language.Finnish)
+}
+
+// Text returns the description of the code in the supported language best
+// matching tag, or an empty string for unknown codes
+func (w PresentWeather) Text(tag language.Tag) string {
+	return localeFor(tag).presentWeathers[w]
+}
+
+// significant reports whether the code describes weather worth
+// mentioning, i.e. not only the development of the sky
+func (w PresentWeather) significant() bool {
+	return w >= PresentWeatherHaze && w <= PresentWeatherTornado
+}
+
+// PresentWeatherCode returns the present weather code of the observation
+func (o Observation) PresentWeatherCode() (PresentWeather, bool) {
+	if !o.PresentWeather.Valid {
+		return 0, false
+	}
+	return PresentWeather(o.PresentWeather.Value), true
+}
+
+func (l *locale) formatPresentWeather(output io.Writer, observations observations) {
+	if wawa, ok := observations["wawa"]; ok && !math.IsNaN(wawa) {
+		if w := PresentWeather(wawa); w.significant() {
+			if text, ok := l.presentWeathers[w]; ok {
+				fmt.Fprintf(output, ", %s", text)
+			}
+		}
+	}
+}
+
+var presentWeatherFinnish = map[PresentWeather]string{
+	0:  "ei merkittäviä sääilmiöitä",
+	1:  "pilvet hälvenemässä",
+	2:  "taivaan tila ennallaan",
+	3:  "pilviä muodostumassa",
+	4:  "auerta, savua tai pölyä",
+	5:  "auerta, savua tai pölyä, näkyvyys alle 1 km",
+	10: "utua",
+	11: "jääneulasia",
+	12: "salamointia kaukana",
+	18: "tuulenpuuskia",
+	20: "sumua edellisen tunnin aikana",
+	21: "sadetta edellisen tunnin aikana",
+	22: "tihkusadetta tai lumijyväsiä edellisen tunnin aikana",
+	23: "vesisadetta edellisen tunnin aikana",
+	24: "lumisadetta edellisen tunnin aikana",
+	25: "jäätävää sadetta edellisen tunnin aikana",
+	26: "ukkosta edellisen tunnin aikana",
+	27: "pöllyävää lunta tai hiekkaa",
+	28: "pöllyävää lunta tai hiekkaa, näkyvyys yli 1 km",
+	29: "pöllyävää lunta tai hiekkaa, näkyvyys alle 1 km",
+	30: "sumua",
+	31: "sumua paikoin",
+	32: "ohenevaa sumua",
+	33: "sumua",
+	34: "tihenevää sumua",
+	35: "kuuraa muodostavaa sumua",
+	40: "sadetta",
+	41: "heikkoa tai kohtalaista sadetta",
+	42: "kovaa sadetta",
+	43: "heikkoa tai kohtalaista vesisadetta",
+	44: "kovaa vesisadetta",
+	45: "heikkoa tai kohtalaista lumisadetta",
+	46: "kovaa lumisadetta",
+	47: "heikkoa tai kohtalaista jäätävää sadetta",
+	48: "kovaa jäätävää sadetta",
+	50: "tihkusadetta",
+	51: "heikkoa tihkusadetta",
+	52: "kohtalaista tihkusadetta",
+	53: "kovaa tihkusadetta",
+	54: "heikkoa jäätävää tihkusadetta",
+	55: "kohtalaista jäätävää tihkusadetta",
+	56: "kovaa jäätävää tihkusadetta",
+	57: "heikkoa tihku- ja vesisadetta",
+	58: "kohtalaista tai kovaa tihku- ja vesisadetta",
+	60: "vesisadetta",
+	61: "heikkoa vesisadetta",
+	62: "kohtalaista vesisadetta",
+	63: "kovaa vesisadetta",
+	64: "heikkoa jäätävää vesisadetta",
+	65: "kohtalaista jäätävää vesisadetta",
+	66: "kovaa jäätävää vesisadetta",
+	67: "heikkoa räntäsadetta",
+	68: "kohtalaista tai kovaa räntäsadetta",
+	70: "lumisadetta",
+	71: "heikkoa lumisadetta",
+	72: "kohtalaista lumisadetta",
+	73: "kovaa lumisadetta",
+	74: "heikkoa jääjyväsadetta",
+	75: "kohtalaista jääjyväsadetta",
+	76: "kovaa jääjyväsadetta",
+	77: "lumijyväsiä",
+	78: "jääkiteitä",
+	80: "sadekuuroja",
+	81: "heikkoja vesikuuroja",
+	82: "kohtalaisia vesikuuroja",
+	83: "kovia vesikuuroja",
+	84: "rajuja vesikuuroja",
+	85: "heikkoja lumikuuroja",
+	86: "kohtalaisia lumikuuroja",
+	87: "kovia lumikuuroja",
+	89: "raekuuroja",
+	90: "ukkosta",
+	91: "heikkoa tai kohtalaista ukkosta",
+	92: "heikkoa tai kohtalaista ukkosta ja sadekuuroja",
+	93: "heikkoa tai kohtalaista ukkosta ja raekuuroja",
+	94: "kovaa ukkosta",
+	95: "kovaa ukkosta ja sadekuuroja",
+	96: "kovaa ukkosta ja raekuuroja",
+	99: "trombi",
+}
+
+var presentWeatherSwedish = map[PresentWeather]string{
+	0:  "inget betydande väder",
+	1:  "molnen upplöses",
+	2:  "oförändrad himmel",
+	3:  "moln bildas",
+	4:  "dis, rök eller damm",
+	5:  "dis, rök eller damm, sikt under 1 km",
+	10: "fuktdis",
+	11: "isnålar",
+	12: "blixtar på avstånd",
+	18: "vindbyar",
+	20: "dimma under den senaste timmen",
+	21: "nederbörd under den senaste timmen",
+	22: "duggregn eller kornsnö under den senaste timmen",
+	23: "regn under den senaste timmen",
+	24: "snöfall under den senaste timmen",
+	25: "underkyld nederbörd under den senaste timmen",
+	26: "åska under den senaste timmen",
+	27: "snödrev eller sanddrev",
+	28: "snödrev eller sanddrev, sikt över 1 km",
+	29: "snödrev eller sanddrev, sikt under 1 km",
+	30: "dimma",
+	31: "dimbankar",
+	32: "avtagande dimma",
+	33: "dimma",
+	34: "tilltagande dimma",
+	35: "dimma med rimfrost",
+	40: "nederbörd",
+	41: "lätt eller måttlig nederbörd",
+	42: "kraftig nederbörd",
+	43: "lätt eller måttligt regn",
+	44: "kraftigt regn",
+	45: "lätt eller måttligt snöfall",
+	46: "kraftigt snöfall",
+	47: "lätt eller måttlig underkyld nederbörd",
+	48: "kraftig underkyld nederbörd",
+	50: "duggregn",
+	51: "lätt duggregn",
+	52: "måttligt duggregn",
+	53: "kraftigt duggregn",
+	54: "lätt underkylt duggregn",
+	55: "måttligt underkylt duggregn",
+	56: "kraftigt underkylt duggregn",
+	57: "lätt duggregn och regn",
+	58: "måttligt eller kraftigt duggregn och regn",
+	60: "regn",
+	61: "lätt regn",
+	62: "måttligt regn",
+	63: "kraftigt regn",
+	64: "lätt underkylt regn",
+	65: "måttligt underkylt regn",
+	66: "kraftigt underkylt regn",
+	67: "lätt snöblandat regn",
+	68: "måttligt eller kraftigt snöblandat regn",
+	70: "snöfall",
+	71: "lätt snöfall",
+	72: "måttligt snöfall",
+	73: "kraftigt snöfall",
+	74: "lätt iskornsfall",
+	75: "måttligt iskornsfall",
+	76: "kraftigt iskornsfall",
+	77: "kornsnö",
+	78: "iskristaller",
+	80: "skurar",
+	81: "lätta regnskurar",
+	82: "måttliga regnskurar",
+	83: "kraftiga regnskurar",
+	84: "mycket kraftiga regnskurar",
+	85: "lätta snöbyar",
+	86: "måttliga snöbyar",
+	87: "kraftiga snöbyar",
+	89: "hagelskurar",
+	90: "åska",
+	91: "lätt eller måttlig åska",
+	92: "lätt eller måttlig åska med skurar",
+	93: "lätt eller måttlig åska med hagel",
+	94: "kraftig åska",
+	95: "kraftig åska med skurar",
+	96: "kraftig åska med hagel",
+	99: "tromb",
+}
+
+var presentWeatherEnglish = map[PresentWeather]string{
+	0:  "no significant weather",
+	1:  "clouds dissolving",
+	2:  "sky unchanged",
+	3:  "clouds forming",
+	4:  "haze, smoke or dust",
+	5:  "haze, smoke or dust, visibility below 1 km",
+	10: "mist",
+	11: "diamond dust",
+	12: "distant lightning",
+	18: "squalls",
+	20: "fog during the past hour",
+	21: "precipitation during the past hour",
+	22: "drizzle or snow grains during the past hour",
+	23: "rain during the past hour",
+	24: "snow during the past hour",
+	25: "freezing precipitation during the past hour",
+	26: "thunderstorm during the past hour",
+	27: "blowing snow or sand",
+	28: "blowing snow or sand, visibility above 1 km",
+	29: "blowing snow or sand, visibility below 1 km",
+	30: "fog",
+	31: "fog in patches",
+	32: "fog thinning",
+	33: "fog",
+	34: "fog thickening",
+	35: "fog depositing rime",
+	40: "precipitation",
+	41: "light or moderate precipitation",
+	42: "heavy precipitation",
+	43: "light or moderate rain",
+	44: "heavy rain",
+	45: "light or moderate snow",
+	46: "heavy snow",
+	47: "light or moderate freezing precipitation",
+	48: "heavy freezing precipitation",
+	50: "drizzle",
+	51: "light drizzle",
+	52: "moderate drizzle",
+	53: "heavy drizzle",
+	54: "light freezing drizzle",
+	55: "moderate freezing drizzle",
+	56: "heavy freezing drizzle",
+	57: "light drizzle and rain",
+	58: "moderate or heavy drizzle and rain",
+	60: "rain",
+	61: "light rain",
+	62: "moderate rain",
+	63: "heavy rain",
+	64: "light freezing rain",
+	65: "moderate freezing rain",
+	66: "heavy freezing rain",
+	67: "light sleet",
+	68: "moderate or heavy sleet",
+	70: "snow",
+	71: "light snow",
+	72: "moderate snow",
+	73: "heavy snow",
+	74: "light ice pellets",
+	75: "moderate ice pellets",
+	76: "heavy ice pellets",
+	77: "snow grains",
+	78: "ice crystals",
+	80: "showers",
+	81: "light rain showers",
+	82: "moderate rain showers",
+	83: "heavy rain showers",
+	84: "violent rain showers",
+	85: "light snow showers",
+	86: "moderate snow showers",
+	87: "heavy snow showers",
+	89: "hail",
+	90: "thunderstorm",
+	91: "light or moderate thunderstorm",
+	92: "light or moderate thunderstorm with showers",
+	93: "light or moderate thunderstorm with hail",
+	94: "heavy thunderstorm",
+	95: "heavy thunderstorm with showers",
+	96: "heavy thunderstorm with hail",
+	99: "tornado",
+}
@@ -0,0 +1,56 @@
+package fmi
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestPresentWeather(t *testing.T) {
+	var tests = []struct {
+		w      PresentWeather
+		tag    language.Tag
+		s      string
+		format string
+	}{
+		{71, language.Finnish, "heikkoa lumisadetta", ", heikkoa lumisadetta"},
+		{30, language.Finnish, "sumua", ", sumua"},
+		{61, language.Swedish, "lätt regn", ", lätt regn"},
+		{95, language.English, "heavy thunderstorm with showers", ", heavy thunderstorm with showers"},
+		{PresentWeatherNoSignificant, language.Finnish, "ei merkittäviä sääilmiöitä", ""},
+		{2, language.English, "sky unchanged", ""},
+		{13, language.Finnish, "", ""},
+	}
+
+	buf := new(bytes.Buffer)
+	for _, test := range tests {
+		if got := test.w.Text(test.tag); got != test.s {
+			t.Errorf("PresentWeather(%d).Text(%s) = '%s'; want '%s'", test.w, test.tag, got, test.s)
+		}
+		localeFor(test.tag).formatPresentWeather(buf, observations{"wawa": float64(test.w)})
+		if buf.String() != test.format {
+			t.Errorf("got '%s', wanted '%s'", buf.String(), test.format)
+		}
+		buf.Reset()
+	}
+
+	if got := PresentWeatherRain.String(); got != "vesisadetta" {
+		t.Errorf("PresentWeatherRain.String() = '%s'; want 'vesisadetta'", got)
+	}
+
+	for w := range presentWeatherFinnish {
+		if presentWeatherSwedish[w] == "" || presentWeatherEnglish[w] == "" {
+			t.Errorf("PresentWeather(%d) is missing a translation", w)
+		}
+	}
+}
+
+func TestObservationPresentWeatherCode(t *testing.T) {
+	if w, ok := (Observation{PresentWeather: Value{81, true}}).PresentWeatherCode(); !ok || w != 81 {
+		t.Errorf("PresentWeatherCode() = %d, %t; want 81, true", w, ok)
+	}
+	if _, ok := (Observation{}).PresentWeatherCode(); ok {
+		t.Errorf("PresentWeatherCode() without a code should not be ok")
+	}
+}
@@ -0,0 +1,128 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/url"
+	"time"
+)
+
+// pressureTendency is the key of the computed pressure tendency in
+// observations. It is not a parameter of FMI's API.
+const pressureTendency = "p_tendency"
+
+// tendencyPeriod is the period pressure tendency is computed over
+const tendencyPeriod = 3 * time.Hour
+
+// fetchPressureHistory fetches the pressure observed tendencyPeriod before
+// the times from start to end at the locations of query q, grouped by time
+// and location. Only the pressure is fetched, so the tendency does not
+// widen the window of the main query.
+func (c *Client) fetchPressureHistory(ctx context.Context, q url.Values, start, end time.Time) (map[time.Time]map[string]observations, error) {
+	q = maps.Clone(q)
+	q.Set("parameters", "p_sea")
+	q.Set("starttime", start.Add(-tendencyPeriod).UTC().Format(time.RFC3339))
+	q.Set("endtime", end.Add(-tendencyPeriod).UTC().Format(time.RFC3339))
+
+	collection, err := c.fetchFeatures(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	c.newQualityChecker().checkCollection(&collection)
+	_, _, history := groupElements(collection)
+	return history, nil
+}
+
+// addPressureTendency computes the pressure tendency of r from the
+// pressure observed tendencyPeriod earlier at its station, or at the
+// station the pressure was merged from. history holds the earlier
+// pressures grouped by time and location, see fetchPressureHistory.
+func addPressureTendency(r row, history map[time.Time]map[string]observations) {
+	pressure, ok := r.Values["p_sea"]
+	if !ok || math.IsNaN(pressure) {
+		return
+	}
+
+	earlier := history[r.Time.Add(-tendencyPeriod)]
+	location := r.Location
+	source, merged := r.Sources["p_sea"]
+	if merged {
+		for l := range earlier {
+			if s := parseStation(l); s.Latitude == source.Latitude && s.Longitude == source.Longitude {
+				location = l
+			}
+		}
+	}
+
+	if p, ok := earlier[location]["p_sea"]; ok && !math.IsNaN(p) {
+		r.Values[pressureTendency] = pressure - p
+		if merged {
+			r.Sources[pressureTendency] = source
+		}
+	}
+}
+
+// addPressureTendencies computes the pressure tendency of each observation
+// in a series from the observation three hours earlier, if there is one
+func addPressureTendencies(observations []Observation) {
+	pressures := make(map[time.Time]float64)
+	for _, o := range observations {
+		if o.Pressure.Valid {
+			pressures[o.Time] = o.Pressure.Value
+		}
+	}
+	for i, o := range observations {
+		if earlier, ok := pressures[o.Time.Add(-tendencyPeriod)]; ok && o.Pressure.Valid {
+			observations[i].PressureTendency = Value{o.Pressure.Value - earlier, true}
+		}
+	}
+}
+
+// pressureTendencyClass classifies a three hour pressure change d (hPa)
+// from falling very rapidly (-4) through steady (0) to rising very rapidly
+// (4), or false if the change is invalid
+func pressureTendencyClass(d float64) (int, bool) {
+	class := 0
+	switch a := math.Abs(d); {
+	case math.IsNaN(d):
+		return 0, false
+	case a < 0.5:
+		return 0, true
+	case a <= 1.5:
+		class = 1
+	case a <= 3.5:
+		class = 2
+	case a <= 6:
+		class = 3
+	default:
+		class = 4
+	}
+	if d < 0 {
+		class = -class
+	}
+	return class, true
+}
+
+func (l *locale) formatPressure(output io.Writer, observations observations) {
+	if p, ok := observations["p_sea"]; ok && !math.IsNaN(p) {
+		fmt.Fprintf(output, l.pressure, p)
+		if d, ok := observations[pressureTendency]; ok {
+			if class, ok := pressureTendencyClass(d); ok {
+				fmt.Fprintf(output, ", %s", l.pressureTendencies[class+4])
+			}
+		}
+	}
+}
+
+func (l *locale) formatVisibility(output io.Writer, observations observations) {
+	if vis, ok := observations["vis"]; ok && !math.IsNaN(vis) && vis >= 0 {
+		if vis < 1000 {
+			io.WriteString(output, l.lowVisibility)
+		} else {
+			fmt.Fprintf(output, l.visibility, vis/1000)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package fmi
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestPressureTendencyClass(t *testing.T) {
+	var tests = []struct {
+		d     float64
+		class int
+		ok    bool
+	}{
+		{0, 0, true},
+		{-0.4, 0, true},
+		{1, 1, true},
+		{-1.5, -1, true},
+		{3, 2, true},
+		{-5, -3, true},
+		{8, 4, true},
+		{-8, -4, true},
+		{math.NaN(), 0, false},
+	}
+	for _, test := range tests {
+		class, ok := pressureTendencyClass(test.d)
+		if class != test.class || ok != test.ok {
+			t.Errorf("pressureTendencyClass(%.1f) = %d, %t; want %d, %t", test.d, class, ok, test.class, test.ok)
+		}
+	}
+}
+
+func TestFormatPressure(t *testing.T) {
+	var tests = []struct {
+		obs observations
+		s   string
+	}{
+		{map[string]float64{}, ""},
+		{map[string]float64{"p_sea": math.NaN()}, ""},
+		{map[string]float64{"p_sea": 1012.3}, ", ilmanpaine 1012.3 hPa"},
+		{map[string]float64{"p_sea": 1012.3, "p_tendency": -4.2}, ", ilmanpaine 1012.3 hPa, paine laskee nopeasti"},
+		{map[string]float64{"p_sea": 1012.3, "p_tendency": 0.2}, ", ilmanpaine 1012.3 hPa, paine pysyy ennallaan"},
+	}
+
+	buf := new(bytes.Buffer)
+	for _, test := range tests {
+		finnish.formatPressure(buf, test.obs)
+		if buf.String() != test.s {
+			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
+		}
+		buf.Reset()
+	}
+}
+
+func TestFormatVisibility(t *testing.T) {
+	var tests = []struct {
+		obs observations
+		s   string
+	}{
+		{map[string]float64{}, ""},
+		{map[string]float64{"vis": 12400}, ", näkyvyys 12 km"},
+		{map[string]float64{"vis": 1000}, ", näkyvyys 1 km"},
+		{map[string]float64{"vis": 400}, ", sumuinen alle 1 km"},
+	}
+
+	buf := new(bytes.Buffer)
+	for _, test := range tests {
+		finnish.formatVisibility(buf, test.obs)
+		if buf.String() != test.s {
+			t.Errorf("got '%s', wanted '%s'", buf.String(), test.s)
+		}
+		buf.Reset()
+	}
+}
+
+func TestAddPressureTendencies(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	obs := make([]Observation, 0)
+	for h := range 5 {
+		obs = append(obs, Observation{Time: start.Add(time.Duration(h) * time.Hour), Pressure: Value{1010 - float64(h), true}})
+	}
+	addPressureTendencies(obs)
+	if obs[2].PressureTendency.Valid {
+		t.Errorf("PressureTendency without an observation three hours earlier = %v; want invalid", obs[2].PressureTendency)
+	}
+	if obs[4].PressureTendency != (Value{-3, true}) {
+		t.Errorf("PressureTendency = %v; want -3", obs[4].PressureTendency)
+	}
+}
+
+func TestObservationsPressureTendency(t *testing.T) {
+	now := time.Now()
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Helsinki Kaisaniemi", Latitude: 60.17523, Longitude: 24.94459,
+		// Pressure falls 2 hPa an hour
+		Func: func(parameter string, ts time.Time) (float64, bool) {
+			switch parameter {
+			case "t2m":
+				return 5, true
+			case "p_sea":
+				return 1000 - 2*ts.Sub(now).Hours(), true
+			case "vis":
+				return 800, true
+			}
+			return 0, false
+		},
+	})
+	defer srv.Close()
+
+	// The tendency is opt-in, as it takes another request
+	obs, err := NewClient(WithBaseURL(srv.URL)).Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations() returned error %v", err)
+	}
+	if obs.PressureTendency.Valid || len(srv.Requests()) != 1 {
+		t.Errorf("Observations() without WithPressureTendency = %v with %d requests; want no tendency with 1 request", obs.PressureTendency, len(srv.Requests()))
+	}
+
+	c := NewClient(WithBaseURL(srv.URL), WithPressureTendency())
+	n := len(srv.Requests())
+	obs, err = c.Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations() returned error %v", err)
+	}
+	if !obs.PressureTendency.Valid || math.Abs(obs.PressureTendency.Value+6) > 0.001 {
+		t.Errorf("PressureTendency = %v; want -6", obs.PressureTendency)
+	}
+	// The main query keeps its 10 minute window and only the pressure is
+	// fetched from three hours earlier
+	requests := srv.Requests()[n:]
+	if n := len(requests); n != 2 {
+		t.Fatalf("Observations() made %d requests; want 2", n)
+	}
+	for i, want := range []time.Duration{10 * time.Minute, 0} {
+		q := requests[i]
+		start, _ := time.Parse(time.RFC3339, q.Get("starttime"))
+		end, _ := time.Parse(time.RFC3339, q.Get("endtime"))
+		if got := end.Sub(start); got != want {
+			t.Errorf("request %d spans %v; want %v", i, got, want)
+		}
+	}
+	if p := requests[1].Get("parameters"); p != "p_sea" {
+		t.Errorf("pressure tendency request parameters = %q; want p_sea", p)
+	}
+	if obs.Visibility != (Value{800, true}) {
+		t.Errorf("Visibility = %v; want 800", obs.Visibility)
+	}
+}
@@ -0,0 +1,269 @@
+package fmi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// QualityFlag tells why a value failed quality control. Values with any
+// flag are rejected: they are left invalid in observations and out of
+// written descriptions.
+type QualityFlag uint8
+
+const (
+	// QualityOutOfRange marks a value outside the plausible range of its
+	// parameter
+	QualityOutOfRange QualityFlag = 1 << iota
+	// QualitySpike marks a value that changed implausibly much since the
+	// previous accepted value of the station
+	QualitySpike
+	// QualityInconsistent marks a value contradicting another parameter,
+	// e.g. a dew point above the temperature
+	QualityInconsistent
+)
+
+// qualityFlagNames names the flags in text and JSON
+var qualityFlagNames = []struct {
+	flag QualityFlag
+	name string
+}{
+	{QualityOutOfRange, "out_of_range"},
+	{QualitySpike, "spike"},
+	{QualityInconsistent, "inconsistent"},
+}
+
+func (f QualityFlag) String() string {
+	names := make([]string, 0, len(qualityFlagNames))
+	for _, n := range qualityFlagNames {
+		if f&n.flag != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// MarshalText encodes the flags as a comma separated list of names, e.g.
+// "spike,inconsistent"
+func (f QualityFlag) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a comma separated list of flag names
+func (f *QualityFlag) UnmarshalText(text []byte) error {
+	*f = 0
+	for _, name := range strings.Split(string(text), ",") {
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, n := range qualityFlagNames {
+			if n.name == name {
+				*f |= n.flag
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("tuntematon laatumerkintä %q", name)
+		}
+	}
+	return nil
+}
+
+// Range is the plausible range of a parameter, bounds included
+type Range struct {
+	Min, Max float64
+}
+
+// QualityLimits configures the quality control of observations
+type QualityLimits struct {
+	// Ranges holds the plausible range of each parameter, keyed by FMI
+	// parameter name
+	Ranges map[string]Range
+	// Steps holds the largest plausible change of each parameter between
+	// observations at most StepInterval apart, keyed by FMI parameter name.
+	// A larger change is accepted once the next observation confirms it.
+	Steps map[string]float64
+	// StepInterval is the longest time between observations compared by
+	// the step test. Longer gaps are not tested.
+	StepInterval time.Duration
+}
+
+// dewPointTolerance is how much the dew point may exceed the temperature
+// (degC) before it is rejected, allowing for rounding
+const dewPointTolerance = 0.5
+
+// gustTolerance is how much the mean wind may exceed the gust (m/s) before
+// the gust is rejected
+const gustTolerance = 0.5
+
+// DefaultQualityLimits returns the limits used unless configured with
+// WithQualityLimits. The ranges cover the extremes observed in Finland with
+// a margin.
+func DefaultQualityLimits() *QualityLimits {
+	return &QualityLimits{
+		Ranges: map[string]Range{
+			"t2m":      {-60, 45},
+			"td":       {-70, 35},
+			"rh":       {0, 100},
+			"ws_10min": {0, 60},
+			"wg_10min": {0, 80},
+			"wd_10min": {0, 360},
+			"r_1h":     {-1, 150},
+			"ri_10min": {0, 500},
+			"snow_aws": {-1, 400},
+			"n_man":    {0, 9},
+			"glob_u":   {-10, 1400},
+			"wawa":     {0, 99},
+			"p_sea":    {900, 1080},
+			"vis":      {0, 100000},
+		},
+		Steps: map[string]float64{
+			"t2m":      10,
+			"td":       10,
+			"snow_aws": 25,
+			"p_sea":    5,
+		},
+		StepInterval: time.Hour,
+	}
+}
+
+// WithQualityLimits sets the limits of the quality control of observations,
+// DefaultQualityLimits by default. nil disables quality control.
+func WithQualityLimits(limits *QualityLimits) Option {
+	return func(c *Client) {
+		c.quality = limits
+	}
+}
+
+// qualityChecker checks rows against the quality limits, remembering the
+// last accepted and the last observed values of each station for the step
+// test
+type qualityChecker struct {
+	limits *QualityLimits
+	last   map[string]map[string]timedValue // by location and parameter
+	raw    map[string]map[string]timedValue // by location and parameter
+}
+
+type timedValue struct {
+	time  time.Time
+	value float64
+}
+
+// newQualityChecker returns a checker for the client's limits, or nil if
+// quality control is disabled
+func (c *Client) newQualityChecker() *qualityChecker {
+	if c.quality == nil {
+		return nil
+	}
+	return &qualityChecker{limits: c.quality, last: make(map[string]map[string]timedValue), raw: make(map[string]map[string]timedValue)}
+}
+
+// check flags the values of r failing quality control, replacing them with
+// NaN. The rows of a station must be checked in time order.
+func (q *qualityChecker) check(r *row) {
+	if q == nil {
+		return
+	}
+
+	flags := make(map[string]QualityFlag)
+	for name, v := range r.Values {
+		if rng, ok := q.limits.Ranges[name]; ok && !math.IsNaN(v) && (v < rng.Min || v > rng.Max) {
+			flags[name] |= QualityOutOfRange
+		}
+	}
+
+	last := q.last[r.Location]
+	if last == nil {
+		last = make(map[string]timedValue)
+		q.last[r.Location] = last
+	}
+	raw := q.raw[r.Location]
+	if raw == nil {
+		raw = make(map[string]timedValue)
+		q.raw[r.Location] = raw
+	}
+	for name, limit := range q.limits.Steps {
+		v, ok := r.Values[name]
+		if !ok || math.IsNaN(v) || flags[name] != 0 {
+			continue
+		}
+		if previous, ok := q.recent(last, name, r.Time); ok && math.Abs(v-previous) > limit {
+			// A jump confirmed by the previous observation is a change
+			// of level rather than a spike
+			if before, ok := q.recent(raw, name, r.Time); !ok || math.Abs(v-before) > limit {
+				flags[name] |= QualitySpike
+			}
+		}
+		raw[name] = timedValue{r.Time, v}
+	}
+
+	accepted := func(name string) (float64, bool) {
+		v, ok := r.Values[name]
+		return v, ok && !math.IsNaN(v) && flags[name] == 0
+	}
+	if t, ok := accepted("t2m"); ok {
+		if td, ok := accepted("td"); ok && td > t+dewPointTolerance {
+			flags["td"] |= QualityInconsistent
+		}
+	}
+	if ws, ok := accepted("ws_10min"); ok {
+		if wg, ok := accepted("wg_10min"); ok && wg < ws-gustTolerance {
+			flags["wg_10min"] |= QualityInconsistent
+		}
+	}
+
+	for name := range q.limits.Steps {
+		if v, ok := accepted(name); ok {
+			last[name] = timedValue{r.Time, v}
+		}
+	}
+
+	for name := range flags {
+		r.Values[name] = math.NaN()
+	}
+	if len(flags) > 0 {
+		r.Quality = flags
+	}
+}
+
+// recent returns the previous value of a parameter in values, if it is
+// recent enough to compare with a value observed at t
+func (q *qualityChecker) recent(values map[string]timedValue, name string, t time.Time) (float64, bool) {
+	previous, ok := values[name]
+	if !ok || !t.After(previous.time) || t.Sub(previous.time) > q.limits.StepInterval {
+		return 0, false
+	}
+	return previous.value, true
+}
+
+// checkCollection checks every row of a collection, replacing rejected
+// values with NaN and recording their flags in collection.Quality
+func (q *qualityChecker) checkCollection(collection *simpleFeatureCollection) {
+	if q == nil {
+		return
+	}
+
+	flags := make(map[string]map[time.Time]map[string]QualityFlag)
+	for _, r := range extractRows(*collection) {
+		q.check(&r)
+		if r.Quality == nil {
+			continue
+		}
+		if flags[r.Location] == nil {
+			flags[r.Location] = make(map[time.Time]map[string]QualityFlag)
+		}
+		flags[r.Location][r.Time] = r.Quality
+	}
+	if len(flags) == 0 {
+		return
+	}
+
+	collection.Quality = flags
+	for i, e := range collection.Elements {
+		if flags[e.Location][e.Time][e.Parameter] != 0 {
+			collection.Elements[i].Value = math.NaN()
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package fmi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestQualityCheck(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		name     string
+		previous observations // 10 minutes earlier, nil if none
+		values   observations
+		want     map[string]QualityFlag
+	}{
+		{"plausible", nil, observations{"t2m": -5, "td": -7, "rh": 85, "ws_10min": 4, "wg_10min": 7}, nil},
+		{"out of range", nil, observations{"t2m": 80, "rh": -3, "p_sea": 1010}, map[string]QualityFlag{"t2m": QualityOutOfRange, "rh": QualityOutOfRange}},
+		{"no precipitation or snow", nil, observations{"r_1h": -1, "snow_aws": -1}, nil},
+		{"negative precipitation", nil, observations{"r_1h": -2}, map[string]QualityFlag{"r_1h": QualityOutOfRange}},
+		{"missing values", nil, observations{"t2m": math.NaN(), "td": 1}, nil},
+		{"spike", observations{"t2m": -5, "p_sea": 1010}, observations{"t2m": 12, "p_sea": 1011}, map[string]QualityFlag{"t2m": QualitySpike}},
+		{"step within limits", observations{"t2m": -5, "snow_aws": 20}, observations{"t2m": 1, "snow_aws": 30}, nil},
+		{"dew point above temperature", nil, observations{"t2m": 2, "td": 4}, map[string]QualityFlag{"td": QualityInconsistent}},
+		{"dew point rounding", nil, observations{"t2m": 2, "td": 2.3}, nil},
+		{"gust below mean wind", nil, observations{"ws_10min": 8, "wg_10min": 5}, map[string]QualityFlag{"wg_10min": QualityInconsistent}},
+		{"rejected temperature not compared", nil, observations{"t2m": -80, "td": 4}, map[string]QualityFlag{"t2m": QualityOutOfRange}},
+	}
+	for _, test := range tests {
+		q := NewClient().newQualityChecker()
+		if test.previous != nil {
+			q.check(&row{Location: "60.1 24.9 ", Time: ts.Add(-10 * time.Minute), Values: test.previous})
+		}
+		r := row{Location: "60.1 24.9 ", Time: ts, Values: test.values}
+		q.check(&r)
+		if diff := cmp.Diff(test.want, r.Quality); diff != "" {
+			t.Errorf("%s: check() flags mismatch (-want +got):\n%s", test.name, diff)
+		}
+		for name := range test.want {
+			if !math.IsNaN(r.Values[name]) {
+				t.Errorf("%s: check() should replace rejected %s with NaN, instead got %v", test.name, name, r.Values[name])
+			}
+		}
+	}
+}
+
+func TestQualityCheckSpikeRecovery(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := NewClient().newQualityChecker()
+	temperatures := []float64{-5, 25, -4.5, -4}
+	var rejected []float64
+	for i, temperature := range temperatures {
+		r := row{Location: "60.1 24.9 ", Time: ts.Add(time.Duration(i) * 10 * time.Minute), Values: observations{"t2m": temperature}}
+		q.check(&r)
+		if r.Quality["t2m"] != 0 {
+			rejected = append(rejected, temperature)
+		}
+	}
+	if diff := cmp.Diff([]float64{25}, rejected); diff != "" {
+		t.Errorf("check() rejected mismatch (-want +got):\n%s", diff)
+	}
+
+	// A new level is accepted once the next observation confirms it
+	q = NewClient().newQualityChecker()
+	temperatures = []float64{-5, 12, 12.2, 12.1}
+	rejected = nil
+	for i, temperature := range temperatures {
+		r := row{Location: "60.1 24.9 ", Time: ts.Add(time.Duration(i) * 10 * time.Minute), Values: observations{"t2m": temperature}}
+		q.check(&r)
+		if r.Quality["t2m"] != 0 {
+			rejected = append(rejected, temperature)
+		}
+	}
+	if diff := cmp.Diff([]float64{12}, rejected); diff != "" {
+		t.Errorf("check() of a level shift rejected mismatch (-want +got):\n%s", diff)
+	}
+
+	// Steps are not tested after a gap longer than StepInterval
+	r := row{Location: "60.1 24.9 ", Time: ts.Add(3 * time.Hour), Values: observations{"t2m": 10}}
+	q.check(&r)
+	if r.Quality != nil {
+		t.Errorf("check() after a gap = %v; want no flags", r.Quality)
+	}
+}
+
+func TestQualityFlagText(t *testing.T) {
+	flags := map[string]QualityFlag{"t2m": QualityOutOfRange, "td": QualitySpike | QualityInconsistent}
+	data, err := json.Marshal(flags)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error %v", err)
+	}
+	if want := `{"t2m":"out_of_range","td":"spike,inconsistent"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s; want %s", data, want)
+	}
+
+	var got map[string]QualityFlag
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() returned error %v", err)
+	}
+	if diff := cmp.Diff(flags, got); diff != "" {
+		t.Errorf("json.Unmarshal() mismatch (-want +got):\n%s", diff)
+	}
+
+	var f QualityFlag
+	if err := f.UnmarshalText([]byte("bogus")); err == nil {
+		t.Errorf("UnmarshalText('bogus') should return an error")
+	}
+}
+
+func TestClientQualityControl(t *testing.T) {
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Helsinki Kaisaniemi", FMISID: 100971, Latitude: 60.17523, Longitude: 24.94459,
+		Values: map[string]float64{"t2m": 80, "rh": 75, "ws_10min": 6, "wg_10min": 3, "r_1h": 0.4, "ri_10min": 900},
+	})
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	obs, err := c.Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations('Helsinki') returned error %v", err)
+	}
+	if obs.Temperature.Valid || obs.WindGust.Valid {
+		t.Errorf("Observations('Helsinki') should reject the temperature and gust, instead got %v and %v", obs.Temperature, obs.WindGust)
+	}
+	if want := map[string]QualityFlag{"t2m": QualityOutOfRange, "wg_10min": QualityInconsistent, "ri_10min": QualityOutOfRange}; !cmp.Equal(want, obs.Quality) {
+		t.Errorf("Observations('Helsinki').Quality = %v; want %v", obs.Quality, want)
+	}
+
+	s, err := c.Weather(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Weather('Helsinki') returned error %v", err)
+	}
+	if strings.Contains(s, "80") || !strings.Contains(s, "lämpötilatiedot puuttuvat") {
+		t.Errorf("Weather('Helsinki') = '%s'; should leave out the rejected temperature", s)
+	}
+	if strings.Contains(s, "NaN") || strings.Contains(s, "mm/h") || !strings.Contains(s, "sateen määrä 0.4 mm") {
+		t.Errorf("Weather('Helsinki') = '%s'; should leave out the rejected rain intensity", s)
+	}
+
+	c = NewClient(WithBaseURL(srv.URL), WithQualityLimits(nil))
+	obs, err = c.Observations(context.Background(), "Helsinki")
+	if err != nil {
+		t.Fatalf("Observations('Helsinki') without quality control returned error %v", err)
+	}
+	if !obs.Temperature.Valid || obs.Quality != nil {
+		t.Errorf("Observations('Helsinki') without quality control = %v, %v; want the raw temperature", obs.Temperature, obs.Quality)
+	}
+}
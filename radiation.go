@@ -0,0 +1,200 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// radiationQuery is the stored query of the radiation stations
+const radiationQuery = "fmi::observations::radiation::simple"
+
+// radiationWindow is how far back the latest radiation observations are
+// searched
+const radiationWindow = time.Hour
+
+// uvIndexFactor converts erythemally weighted UV irradiance (W/m2) to the
+// UV index
+const uvIndexFactor = 40
+
+// Radiation holds the solar radiation observed at a radiation station as
+// one minute averages
+type Radiation struct {
+	Station Station   `json:"station"`
+	Time    time.Time `json:"time"`
+
+	Global   Value `json:"global"`   // W/m2, global radiation
+	Diffuse  Value `json:"diffuse"`  // W/m2, diffuse radiation
+	UV       Value `json:"uv"`       // W/m2, erythemally weighted UV irradiance
+	Sunshine Value `json:"sunshine"` // s, sunshine during the minute
+}
+
+// radiationParameters maps the parameters of radiation stations to
+// Radiation fields
+var radiationParameters = map[string]func(*Radiation) *Value{
+	"GLOB_1MIN": func(r *Radiation) *Value { return &r.Global },
+	"DIFF_1MIN": func(r *Radiation) *Value { return &r.Diffuse },
+	"UVB_U":     func(r *Radiation) *Value { return &r.UV },
+	"SUND_1MIN": func(r *Radiation) *Value { return &r.Sunshine },
+}
+
+var radiationMeasures = []string{"GLOB_1MIN", "DIFF_1MIN", "UVB_U", "SUND_1MIN"}
+
+// UVIndex returns the UV index derived from the UV irradiance, or false if
+// the irradiance is missing
+func (r Radiation) UVIndex() (float64, bool) {
+	if !r.UV.Valid {
+		return 0, false
+	}
+	return max(r.UV.Value*uvIndexFactor, 0), true
+}
+
+// Radiation returns the latest radiation observations for a place
+func (c *Client) Radiation(ctx context.Context, place string) (Radiation, error) {
+	return c.RadiationAt(ctx, Place(place))
+}
+
+// RadiationAt returns the latest radiation observations of the radiation
+// station nearest to a location. Time is the newest time with data.
+// Parameters missing from it have their newest earlier value. Only
+// radiation stations observe radiation, so the station may be far from the
+// location.
+func (c *Client) RadiationAt(ctx context.Context, loc Location) (Radiation, error) {
+	r, station, err := c.fetchLatestOf(ctx, loc, radiationQuery, radiationMeasures, radiationWindow, 10*time.Minute, RadiationStation, extractNewestValues)
+	if err != nil {
+		return Radiation{}, err
+	}
+
+	rad := Radiation{Station: station, Time: r.Time}
+	setValues(&rad, r.Values, radiationParameters)
+	return rad, nil
+}
+
+// SunshineHours returns the hours of sunshine observed at a location on
+// the day of t in Finnish time, up to now for today
+func (c *Client) SunshineHours(ctx context.Context, loc Location, t time.Time) (float64, error) {
+	if !loc.valid() {
+		return 0, ErrNoPlace
+	}
+
+	tz, err := helsinki()
+	if err != nil {
+		return 0, err
+	}
+	start := dayOf(t, tz)
+	end := start.AddDate(0, 0, 1).Add(-time.Minute)
+	if now := time.Now().Truncate(time.Minute); end.After(now) {
+		end = now
+	}
+	if end.Before(start) {
+		return 0, fmt.Errorf("%w: päivä on tulevaisuudessa", ErrBadRequest)
+	}
+
+	q := observationQuery(loc, []string{"SUND_1MIN"}, start, end, time.Minute)
+	q.Set("storedquery_id", radiationQuery)
+	collection, err := c.fetchFeatures(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only the nearest station is counted
+	rows := extractRows(collection)
+	seconds, valid := 0.0, false
+	for _, r := range rows {
+		if v := r.Values["SUND_1MIN"]; r.Location == rows[0].Location && !math.IsNaN(v) {
+			seconds += v
+			valid = true
+		}
+	}
+	if !valid {
+		return 0, ErrNoData
+	}
+	return seconds / 3600, nil
+}
+
+// RadiationSummary returns the latest UV index and radiation and today's
+// sunshine hours for a place as a written description in Finnish
+func (c *Client) RadiationSummary(ctx context.Context, place string) (string, error) {
+	if place == "" {
+		return "", ErrNoPlace
+	}
+
+	rad, err := c.Radiation(ctx, place)
+	if err != nil {
+		return "", err
+	}
+
+	loc := Place(place)
+	if rad.Station.FMISID != 0 {
+		loc = FMISID(rad.Station.FMISID)
+	}
+	sunshine, err := c.SunshineHours(ctx, loc, time.Now())
+	switch {
+	case errors.Is(err, ErrNoData):
+		// Sunshine is auxiliary, missing data only leaves it out
+		sunshine = math.NaN()
+	case err != nil:
+		return "", err
+	}
+
+	return formatRadiation(place, rad, sunshine), nil
+}
+
+// uvIndexClasses names the classes of the UV index
+var uvIndexClasses = [...]string{"heikko", "kohtalainen", "voimakas", "hyvin voimakas", "äärimmäisen voimakas"}
+
+// uvIndexClass classifies a UV index from low (0) to extreme (4), or
+// returns -1 if the index is invalid
+func uvIndexClass(index float64) int {
+	switch i := math.Round(index); {
+	case math.IsNaN(index) || i < 0:
+		return -1
+	case i <= 2:
+		return 0
+	case i <= 5:
+		return 1
+	case i <= 7:
+		return 2
+	case i <= 10:
+		return 3
+	}
+	return 4
+}
+
+// formatRadiation returns a string representation of radiation and the
+// day's sunshine hours at a place in Finnish. NaN sunshine is left out.
+func formatRadiation(place string, rad Radiation, sunshine float64) string {
+	var output strings.Builder
+
+	c := cases.Title(language.Finnish)
+
+	fmt.Fprintf(&output, "Auringonsäteily paikassa %s: ", c.String(strings.ToLower(place)))
+
+	parts := make([]string, 0, 4)
+	if index, ok := rad.UVIndex(); ok {
+		parts = append(parts, fmt.Sprintf("UV-indeksi %.f, %s", index, uvIndexClasses[uvIndexClass(index)]))
+	}
+	if rad.Global.Valid {
+		parts = append(parts, fmt.Sprintf("kokonaissäteily %.f W/m²", rad.Global.Value))
+	}
+	if rad.Diffuse.Valid {
+		parts = append(parts, fmt.Sprintf("hajasäteily %.f W/m²", rad.Diffuse.Value))
+	}
+	if !math.IsNaN(sunshine) {
+		parts = append(parts, fmt.Sprintf("auringonpaistetta tänään %.1f h", sunshine))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "säteilytiedot puuttuvat")
+	}
+	output.WriteString(strings.Join(parts, ", "))
+
+	output.WriteString(formatStationName(rad.Station))
+
+	return output.String()
+}
@@ -0,0 +1,118 @@
+package fmi
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestUVIndexClass(t *testing.T) {
+	var tests = []struct {
+		index float64
+		class int
+	}{
+		{math.NaN(), -1},
+		{0, 0},
+		{2.4, 0},
+		{2.5, 1},
+		{5, 1},
+		{7.4, 2},
+		{10, 3},
+		{11, 4},
+	}
+	for _, test := range tests {
+		if got := uvIndexClass(test.index); got != test.class {
+			t.Errorf("uvIndexClass(%v) = %d; want %d", test.index, got, test.class)
+		}
+	}
+}
+
+func TestFormatRadiation(t *testing.T) {
+	var tests = []struct {
+		rad      Radiation
+		sunshine float64
+		s        string
+	}{
+		{
+			Radiation{Station: Station{Name: "Helsinki Kumpula"}, Global: Value{652.4, true}, Diffuse: Value{118, true}, UV: Value{0.125, true}},
+			6.52,
+			"Auringonsäteily paikassa Helsinki: UV-indeksi 5, kohtalainen, kokonaissäteily 652 W/m², hajasäteily 118 W/m², auringonpaistetta tänään 6.5 h (Helsinki Kumpula)",
+		},
+		{
+			Radiation{UV: Value{-0.001, true}},
+			math.NaN(),
+			"Auringonsäteily paikassa Helsinki: UV-indeksi 0, heikko",
+		},
+		{Radiation{}, math.NaN(), "Auringonsäteily paikassa Helsinki: säteilytiedot puuttuvat"},
+	}
+	for _, test := range tests {
+		if got := formatRadiation("helsinki", test.rad, test.sunshine); got != test.s {
+			t.Errorf("formatRadiation(%v, %v) = '%s'; want '%s'", test.rad, test.sunshine, got, test.s)
+		}
+	}
+}
+
+func TestClientRadiation(t *testing.T) {
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Helsinki Kumpula", FMISID: 101004, Latitude: 60.20307, Longitude: 24.96131, Networks: []string{"Auringonsäteilyasema"},
+		Values: map[string]float64{"GLOB_1MIN": 650, "DIFF_1MIN": 120, "UVB_U": 0.16, "SUND_1MIN": 30},
+	})
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	rad, err := c.RadiationAt(ctx, FMISID(101004))
+	if err != nil {
+		t.Fatalf("RadiationAt() returned error %v", err)
+	}
+	if rad.Global != (Value{650, true}) || rad.Diffuse != (Value{120, true}) || rad.Station.Name != "Helsinki Kumpula" {
+		t.Errorf("RadiationAt() = %+v; want global 650 and diffuse 120 W/m2 at Helsinki Kumpula", rad)
+	}
+	if index, ok := rad.UVIndex(); !ok || math.Abs(index-6.4) > 1e-9 {
+		t.Errorf("RadiationAt().UVIndex() = %v, %v; want 6.4", index, ok)
+	}
+
+	hours, err := c.SunshineHours(ctx, FMISID(101004), time.Date(2024, 7, 1, 12, 0, 0, 0, finnishTime))
+	if err != nil {
+		t.Fatalf("SunshineHours() returned error %v", err)
+	}
+	if hours != 12 {
+		t.Errorf("SunshineHours() = %v; want 12 hours for half of every minute", hours)
+	}
+
+	s, err := c.RadiationSummary(ctx, "Helsinki")
+	if err != nil {
+		t.Fatalf("RadiationSummary('Helsinki') returned error %v", err)
+	}
+	if want := "UV-indeksi 6, voimakas, kokonaissäteily 650 W/m²"; !strings.Contains(s, want) || !strings.Contains(s, "auringonpaistetta tänään") {
+		t.Errorf("RadiationSummary('Helsinki') = '%s'; should contain '%s' and today's sunshine", s, want)
+	}
+}
+
+func TestClientRadiationNewest(t *testing.T) {
+	newest := time.Now().UTC().Truncate(10 * time.Minute)
+	srv := fmitest.NewServer(fmitest.Station{
+		Name: "Helsinki Kumpula", FMISID: 101004, Latitude: 60.20307, Longitude: 24.96131, Networks: []string{"Auringonsäteilyasema"},
+		// The newest time has no UV yet
+		Func: func(parameter string, ts time.Time) (float64, bool) {
+			if !ts.Before(newest) {
+				return 700, parameter == "GLOB_1MIN"
+			}
+			return map[string]float64{"GLOB_1MIN": 400, "UVB_U": 0.1}[parameter], parameter == "GLOB_1MIN" || parameter == "UVB_U"
+		},
+	})
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	rad, err := c.RadiationAt(context.Background(), FMISID(101004))
+	if err != nil {
+		t.Fatalf("RadiationAt() returned error %v", err)
+	}
+	if !rad.Time.Equal(newest) || rad.Global != (Value{700, true}) || rad.UV != (Value{0.1, true}) || rad.Diffuse.Valid {
+		t.Errorf("RadiationAt() = %+v; want global 700 at %v and UV 0.1 from before", rad, newest)
+	}
+}
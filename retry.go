@@ -0,0 +1,199 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// DefaultRetryDelay is the delay before the first retry, later retries
+// double it
+const DefaultRetryDelay = 500 * time.Millisecond
+
+// maxRetryDelay limits the delay between retries
+const maxRetryDelay = 30 * time.Second
+
+// WithRetries retries requests failing with a transient error, i.e. a
+// timeout, network error, 5xx or 429 response, up to n times. The delay
+// before a retry starts from delay, doubles on each retry and is jittered,
+// unless FMI asks for a delay with Retry-After. A Retry-After longer than
+// 30 seconds is not waited for, the error is returned instead.
+func WithRetries(n int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.retryDelay = delay
+	}
+}
+
+// DefaultDailyLimit is the number of requests FMI allows in a day
+const DefaultDailyLimit = 20000
+
+// WithRateLimit limits the client to rate requests per second on average,
+// allowing bursts of burst requests. Requests wait for their turn. FMI
+// allows 600 requests in five minutes, i.e. a rate of 2, and 20 000
+// requests per day, which is also enforced unless changed with
+// WithDailyLimit.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.rate = rate
+		c.burst = burst
+	}
+}
+
+// WithDailyLimit sets the number of requests WithRateLimit allows in any
+// 24 hours, DefaultDailyLimit by default. A limit of zero or less leaves
+// only the rate limit.
+func WithDailyLimit(n int) Option {
+	return func(c *Client) {
+		c.dailyLimit = n
+	}
+}
+
+// retry makes a request, retrying transient failures
+func (c *Client) retry(ctx context.Context, endpoint string) ([]byte, error) {
+	var body []byte
+	err := c.withRetries(ctx, func() (err error) {
+		body, err = c.request(ctx, endpoint)
+		return err
+	})
+	return body, err
+}
+
+// withRetries calls attempt, waiting for the rate limit before each call
+// and retrying transient failures
+func (c *Client) withRetries(ctx context.Context, attempt func() error) error {
+	delay := c.retryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	for n := 0; ; n++ {
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		err := attempt()
+		if err == nil || n >= c.retries || !errors.Is(err, ErrUnavailable) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff(delay, n)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			// Longer waits are left to the caller
+			if apiErr.RetryAfter > maxRetryDelay {
+				return err
+			}
+			wait = apiErr.RetryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+		if !sleep(ctx, wait) {
+			return err
+		}
+	}
+}
+
+// backoff returns the delay before retry attempt+1, doubling delay on
+// every attempt and jittering it to between half and all of that
+func backoff(delay time.Duration, attempt int) time.Duration {
+	d := min(delay<<attempt, maxRetryDelay)
+	if d <= 0 {
+		d = maxRetryDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// limiter is a token bucket rate limiter with an optional second bucket
+// for the daily quota. A request takes a token from every bucket.
+type limiter struct {
+	mu      sync.Mutex
+	buckets []*bucket
+}
+
+// bucket is a token bucket
+type bucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns a limiter allowing rate requests per second in bursts
+// of burst, and daily requests in any 24 hours if daily is positive. The
+// daily bucket holds a tenth of the quota and refills the rest over the
+// day, so that a client waiting for its turn cannot go over it.
+func newLimiter(now time.Time, rate float64, burst int, daily int) *limiter {
+	l := &limiter{buckets: []*bucket{newBucket(now, rate, float64(max(burst, 1)))}}
+	if daily > 0 {
+		b := max(float64(daily)/10, 1)
+		l.buckets = append(l.buckets, newBucket(now, (float64(daily)-b)/(24*time.Hour).Seconds(), b))
+	}
+	return l
+}
+
+func newBucket(now time.Time, rate, burst float64) *bucket {
+	return &bucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// reserve takes a token from every bucket, returning how long to wait
+// until they are all available
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	for _, b := range l.buckets {
+		if b.rate <= 0 {
+			continue
+		}
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		b.tokens--
+		if b.tokens < 0 {
+			wait = max(wait, time.Duration(-b.tokens/b.rate*float64(time.Second)))
+		}
+	}
+	return wait
+}
+
+// cancel returns the tokens reserved by a request that did not wait for
+// them
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, b := range l.buckets {
+		b.tokens = min(b.burst, b.tokens+1)
+	}
+}
+
+// wait blocks until a request may be made or ctx is done
+func (l *limiter) wait(ctx context.Context) error {
+	d := l.reserve(time.Now())
+	if d == 0 {
+		return nil
+	}
+	if !sleep(ctx, d) {
+		l.cancel()
+		return fmt.Errorf("%w: %w", ErrUnavailable, ctx.Err())
+	}
+	return nil
+}
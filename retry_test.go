@@ -0,0 +1,167 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestClientRetries(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithRetries(2, time.Millisecond))
+
+	var tests = []struct {
+		failures int
+		status   int
+		code     string
+		requests int
+		err      error
+	}{
+		{2, http.StatusServiceUnavailable, "ServiceUnavailable", 3, nil},
+		{3, http.StatusBadGateway, "", 3, ErrUnavailable},
+		{1, http.StatusTooManyRequests, "", 2, nil},
+		{1, http.StatusBadRequest, "InvalidParameterValue", 1, ErrBadRequest},
+	}
+	for _, test := range tests {
+		n := len(srv.Requests())
+		srv.FailNext(test.failures, test.status, test.code)
+		_, err := c.Observations(context.Background(), "Helsinki")
+		if !errors.Is(err, test.err) || (test.err == nil && err != nil) {
+			t.Errorf("Observations() after %d HTTP %d returned error %v; want %v", test.failures, test.status, err, test.err)
+		}
+		if got := len(srv.Requests()) - n; got != test.requests {
+			t.Errorf("Observations() after %d HTTP %d made %d requests; want %d", test.failures, test.status, got, test.requests)
+		}
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+	srv.SetRetryAfter(time.Second)
+	c := NewClient(WithBaseURL(srv.URL), WithRetries(1, time.Millisecond))
+
+	srv.FailNext(1, http.StatusTooManyRequests, "")
+	start := time.Now()
+	if _, err := c.Observations(context.Background(), "Helsinki"); err != nil {
+		t.Errorf("Observations() returned error %v", err)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Errorf("Observations() retried after %v; want Retry-After 1s", d)
+	}
+
+	// A retry that would not finish before the deadline is not made
+	srv.FailNext(1, http.StatusTooManyRequests, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.Observations(ctx, "Helsinki"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Observations() with a short deadline returned error %v; want ErrUnavailable", err)
+	}
+
+	// Nor is one after a Retry-After longer than maxRetryDelay
+	srv.SetRetryAfter(time.Hour)
+	srv.FailNext(1, http.StatusTooManyRequests, "")
+	n := len(srv.Requests())
+	start = time.Now()
+	if _, err := c.Observations(context.Background(), "Helsinki"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Observations() after Retry-After 1h returned error %v; want ErrUnavailable", err)
+	}
+	if got := len(srv.Requests()) - n; got != 1 || time.Since(start) > maxRetryDelay {
+		t.Errorf("Observations() after Retry-After 1h made %d requests in %v; want 1 without waiting", got, time.Since(start))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := range 10 {
+		want := min(100*time.Millisecond<<attempt, maxRetryDelay)
+		if d := backoff(100*time.Millisecond, attempt); d < want/2 || d > want {
+			t.Errorf("backoff(100ms, %d) = %v; want between %v and %v", attempt, d, want/2, want)
+		}
+	}
+	if d := backoff(time.Second, 100); d < maxRetryDelay/2 || d > maxRetryDelay {
+		t.Errorf("backoff(1s, 100) = %v; want at most %v", d, maxRetryDelay)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newLimiter(now, 2, 2, 0)
+
+	var tests = []struct {
+		after time.Duration
+		wait  time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+		{0, time.Second},
+		{2 * time.Second, 0},
+		{2 * time.Second, 0},
+		{2 * time.Second, 500 * time.Millisecond},
+	}
+	for i, test := range tests {
+		if got := l.reserve(now.Add(test.after)); got != test.wait {
+			t.Errorf("request %d reserve() = %v; want %v", i, got, test.wait)
+		}
+	}
+}
+
+func TestLimiterDaily(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newLimiter(start, 2, 10, 1000)
+
+	// A client always waiting for its turn stays within the daily quota
+	// in any 24 hours, not only within the rate limit
+	var times []time.Time
+	for now := start; now.Before(start.Add(48 * time.Hour)); {
+		now = now.Add(l.reserve(now))
+		times = append(times, now)
+	}
+	for i, t0 := range times {
+		j := i
+		for j < len(times) && times[j].Sub(t0) < 24*time.Hour {
+			j++
+		}
+		if n := j - i; n > 1000 {
+			t.Fatalf("%d requests in 24 hours from %v; want at most 1000", n, t0)
+		}
+	}
+	if n := len(times); n < 1500 {
+		t.Errorf("%d requests in 48 hours; want at least 1500", n)
+	}
+	// Within the daily quota the rate limit applies
+	if d := times[10].Sub(times[9]); d != 500*time.Millisecond {
+		t.Errorf("11th request waited %v; want 500ms", d)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	srv := fmitest.NewServer(testStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithRateLimit(20, 1))
+
+	start := time.Now()
+	for range 3 {
+		if _, err := c.Observations(context.Background(), "Helsinki"); err != nil {
+			t.Fatalf("Observations() returned error %v", err)
+		}
+	}
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("3 requests at 20 per second took %v; want at least 100ms", d)
+	}
+
+	c = NewClient(WithBaseURL(srv.URL), WithRateLimit(0.001, 1))
+	if _, err := c.Observations(context.Background(), "Helsinki"); err != nil {
+		t.Fatalf("Observations() returned error %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Observations(ctx, "Helsinki"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Observations() waiting for the rate limit past the deadline returned error %v; want ErrUnavailable", err)
+	}
+}
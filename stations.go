@@ -0,0 +1,208 @@
+package fmi
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StationType is a set of station networks a station belongs to
+type StationType int
+
+// Station networks, a station can belong to several
+const (
+	WeatherStation       StationType = 1 << iota // automatic weather station
+	PrecipitationStation                         // precipitation station
+	SeaLevelStation                              // mareograph
+	RadiationStation                             // solar radiation station
+	WaveStation                                  // wave buoy
+	AirQualityStation                            // urban air quality station
+
+	AnyStation StationType = 0
+)
+
+// Has reports whether the set contains every network of u
+func (t StationType) Has(u StationType) bool {
+	return t&u == u
+}
+
+// stationNetworks maps FMI's network names to station types
+var stationNetworks = map[string]StationType{
+	"Automaattinen sääasema": WeatherStation,
+	"Sadeasema":              PrecipitationStation,
+	"Mareografi":             SeaLevelStation,
+	"Auringonsäteilyasema":   RadiationStation,
+	"Aaltopoiju":             WaveStation,
+	"Ilmanlaatuasema":        AirQualityStation,
+}
+
+// stationCatalogTTL is how long a fetched station catalog is used
+const stationCatalogTTL = 24 * time.Hour
+
+// stationsFlight is the flightGroup key of catalog fetches. It is not an
+// address, so it does not clash with the keys of other requests.
+const stationsFlight = "stations"
+
+// Catalog lists FMI's stations. Get the catalog with Client.Stations.
+type Catalog struct {
+	Stations []Station
+	Fetched  time.Time
+}
+
+type stationFeatureCollection struct {
+	Facilities []stationFacility `xml:"member>EnvironmentalMonitoringFacility"`
+}
+
+type stationFacility struct {
+	Identifier string `xml:"identifier"`
+	Names      []struct {
+		CodeSpace string `xml:"codeSpace,attr"`
+		Value     string `xml:",chardata"`
+	} `xml:"name"`
+	Position string `xml:"representativePoint>Point>pos"`
+	Networks []struct {
+		Title string `xml:"http://www.w3.org/1999/xlink title,attr"`
+	} `xml:"belongsTo"`
+}
+
+// station returns the facility as a Station
+func (f stationFacility) station() Station {
+	s := parseStation(f.Position)
+	s.FMISID, _ = strconv.Atoi(strings.TrimSpace(f.Identifier))
+	for _, name := range f.Names {
+		switch {
+		case strings.HasSuffix(name.CodeSpace, "/name"):
+			s.Name = strings.TrimSpace(name.Value)
+		case strings.HasSuffix(name.CodeSpace, "/wmo"):
+			s.WMO, _ = strconv.Atoi(strings.TrimSpace(name.Value))
+		}
+	}
+	for _, network := range f.Networks {
+		s.Type |= stationNetworks[network.Title]
+	}
+	return s
+}
+
+func parseStationCatalog(data []byte) (*Catalog, error) {
+	var collection stationFeatureCollection
+	if err := xml.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	catalog := &Catalog{Stations: make([]Station, 0, len(collection.Facilities))}
+	for _, f := range collection.Facilities {
+		catalog.Stations = append(catalog.Stations, f.station())
+	}
+	return catalog, nil
+}
+
+// Stations returns the catalog of FMI's stations. The catalog is fetched
+// once and cached for a day. Each call returns a copy, so callers may sort
+// or filter its stations.
+func (c *Client) Stations(ctx context.Context) (*Catalog, error) {
+	catalog, err := c.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Catalog{Stations: slices.Clone(catalog.Stations), Fetched: catalog.Fetched}, nil
+}
+
+// catalog returns the cached catalog shared by the client's lookups,
+// fetching it if needed. It must not be modified.
+func (c *Client) catalog(ctx context.Context) (*Catalog, error) {
+	catalog := c.cachedStations()
+	if catalog != nil {
+		return catalog, nil
+	}
+
+	q := url.Values{}
+	q.Set("service", "WFS")
+	q.Set("version", "2.0.0")
+	q.Set("request", "getFeature")
+	q.Set("storedquery_id", "fmi::ef::stations")
+
+	// Concurrent callers share one fetch, which is done without holding
+	// the lock
+	body, err := c.flights.do(ctx, stationsFlight, func(ctx context.Context) ([]byte, error) {
+		return c.get(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	catalog, err = parseStationCatalog(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(catalog.Stations) == 0 {
+		return nil, ErrNoData
+	}
+	catalog.Fetched = time.Now()
+
+	c.stationsMu.Lock()
+	c.stations = catalog
+	c.stationsMu.Unlock()
+	return catalog, nil
+}
+
+// cachedStations returns the fetched station catalog, or nil if it has not
+// been fetched or is out of date. It must not be modified.
+func (c *Client) cachedStations() *Catalog {
+	c.stationsMu.Lock()
+	defer c.stationsMu.Unlock()
+	if c.stations == nil || time.Since(c.stations.Fetched) >= stationCatalogTTL {
+		return nil
+	}
+	return c.stations
+}
+
+// Filter returns the stations belonging to every network of t
+func (c *Catalog) Filter(t StationType) []Station {
+	stations := make([]Station, 0)
+	for _, s := range c.Stations {
+		if s.Type.Has(t) {
+			stations = append(stations, s)
+		}
+	}
+	return stations
+}
+
+// Nearest returns the n stations of type t nearest to a coordinate,
+// nearest first, or nil if n is not positive
+func (c *Catalog) Nearest(lat, lon float64, n int, t StationType) []Station {
+	if n <= 0 {
+		return nil
+	}
+	stations := c.Filter(t)
+	slices.SortStableFunc(stations, func(a, b Station) int {
+		return cmp.Compare(Distance(lat, lon, a.Latitude, a.Longitude), Distance(lat, lon, b.Latitude, b.Longitude))
+	})
+	return stations[:min(n, len(stations))]
+}
+
+// ByName returns the station with a name, ignoring case
+func (c *Catalog) ByName(name string) (Station, bool) {
+	return c.find(func(s Station) bool { return strings.EqualFold(s.Name, name) })
+}
+
+// ByFMISID returns the station with an FMI station id
+func (c *Catalog) ByFMISID(id int) (Station, bool) {
+	return c.find(func(s Station) bool { return s.FMISID == id })
+}
+
+// ByWMO returns the station with a WMO station id
+func (c *Catalog) ByWMO(id int) (Station, bool) {
+	return c.find(func(s Station) bool { return s.WMO == id })
+}
+
+func (c *Catalog) find(match func(Station) bool) (Station, bool) {
+	if i := slices.IndexFunc(c.Stations, match); i >= 0 {
+		return c.Stations[i], true
+	}
+	return Station{}, false
+}
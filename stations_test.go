@@ -0,0 +1,117 @@
+package fmi
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kari/fmi/fmitest"
+)
+
+var testCatalogStations = []fmitest.Station{
+	{Name: "Helsinki Kaisaniemi", FMISID: 100971, WMO: 2978, Latitude: 60.17523, Longitude: 24.94459},
+	{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, WMO: 2974, Latitude: 60.3267, Longitude: 24.95675, Networks: []string{"Automaattinen sääasema", "Auringonsäteilyasema"}},
+	{Name: "Helsinki Kaivopuisto", FMISID: 132310, Latitude: 60.15363, Longitude: 24.95622, Networks: []string{"Mareografi"}},
+	{Name: "Espoo Nuuksio", FMISID: 852678, Latitude: 60.29, Longitude: 24.57, Networks: []string{"Sadeasema"}},
+}
+
+func TestStations(t *testing.T) {
+	srv := fmitest.NewServer(testCatalogStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	catalog, err := c.Stations(context.Background())
+	if err != nil {
+		t.Fatalf("Stations() returned error %v", err)
+	}
+	if _, err := c.Stations(context.Background()); err != nil || len(srv.Requests()) != 1 {
+		t.Errorf("Stations() should be cached, instead got %d requests and error %v", len(srv.Requests()), err)
+	}
+
+	want := Station{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, WMO: 2974, Type: WeatherStation | RadiationStation, Latitude: 60.3267, Longitude: 24.95675}
+	if got, ok := catalog.ByFMISID(100968); !ok || !cmp.Equal(got, want) {
+		t.Errorf("ByFMISID(100968) = %v, %t; want %v", got, ok, want)
+	}
+	if got, ok := catalog.ByWMO(2974); !ok || !cmp.Equal(got, want) {
+		t.Errorf("ByWMO(2974) = %v, %t; want %v", got, ok, want)
+	}
+	if got, ok := catalog.ByName("helsinki-vantaa lentoasema"); !ok || !cmp.Equal(got, want) {
+		t.Errorf("ByName('helsinki-vantaa lentoasema') = %v, %t; want %v", got, ok, want)
+	}
+	if got, ok := catalog.ByName("Narnia"); ok {
+		t.Errorf("ByName('Narnia') = %v; want not found", got)
+	}
+
+	var tests = []struct {
+		n     int
+		t     StationType
+		names []string
+	}{
+		{2, AnyStation, []string{"Helsinki Kaisaniemi", "Helsinki Kaivopuisto"}},
+		{10, WeatherStation, []string{"Helsinki Kaisaniemi", "Helsinki-Vantaa lentoasema"}},
+		{1, PrecipitationStation, []string{"Espoo Nuuksio"}},
+		{1, SeaLevelStation, []string{"Helsinki Kaivopuisto"}},
+		{1, WeatherStation | RadiationStation, []string{"Helsinki-Vantaa lentoasema"}},
+		{0, AnyStation, []string{}},
+		{-1, AnyStation, []string{}},
+	}
+	for _, test := range tests {
+		names := make([]string, 0)
+		for _, s := range catalog.Nearest(60.17, 24.94, test.n, test.t) {
+			names = append(names, s.Name)
+		}
+		if diff := cmp.Diff(test.names, names); diff != "" {
+			t.Errorf("Nearest(%d, %d) mismatch (-want +got):\n%s", test.n, test.t, diff)
+		}
+	}
+}
+
+func TestStationsCopy(t *testing.T) {
+	srv := fmitest.NewServer(testCatalogStations...)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	catalog, err := c.Stations(context.Background())
+	if err != nil {
+		t.Fatalf("Stations() returned error %v", err)
+	}
+	// Changing a returned catalog does not change the cached one
+	slices.Reverse(catalog.Stations)
+	catalog.Stations = catalog.Stations[:1]
+
+	catalog, err = c.Stations(context.Background())
+	if err != nil {
+		t.Fatalf("Stations() returned error %v", err)
+	}
+	if len(catalog.Stations) != len(testCatalogStations) || catalog.Stations[0].Name != "Helsinki Kaisaniemi" {
+		t.Errorf("Stations() after changing a returned catalog = %v; want the original catalog", catalog.Stations)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("Stations() made %d requests; want 1", n)
+	}
+}
+
+func TestStationsConcurrent(t *testing.T) {
+	srv := fmitest.NewServer(testCatalogStations...)
+	defer srv.Close()
+	srv.SetDelay(50 * time.Millisecond)
+	c := NewClient(WithBaseURL(srv.URL))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Stations(context.Background()); err != nil {
+				t.Errorf("Stations() returned error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("concurrent Stations() made %d requests; want 1", n)
+	}
+}
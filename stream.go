@@ -0,0 +1,189 @@
+package fmi
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// StreamTimeSeries returns the observations between start and end,
+// inclusive, at the given timestep for every station matching a location,
+// like TimeSeries. Instead of collecting the series in memory, responses
+// are decoded as they arrive and observations yielded one at a time, so
+// long downloads run in constant memory.
+//
+// Long ranges are split into several requests. Within a request,
+// observations are ordered by station and then by time. Streams always use
+// the simple response format and the client's timeout and cache do not
+// apply, limit the download with ctx instead. An error ends the stream.
+func (c *Client) StreamTimeSeries(ctx context.Context, loc Location, start, end time.Time, step time.Duration) iter.Seq2[Observation, error] {
+	return func(yield func(Observation, error) bool) {
+		if !loc.valid() {
+			yield(Observation{}, ErrNoPlace)
+			return
+		}
+		if step < time.Minute {
+			yield(Observation{}, fmt.Errorf("%w: aika-askel on alle minuutin", ErrBadRequest))
+			return
+		}
+		if end.Before(start) {
+			yield(Observation{}, fmt.Errorf("%w: alkuaika on loppuajan jälkeen", ErrBadRequest))
+			return
+		}
+
+		pressures := make(pressureHistory)
+		checker := c.newQualityChecker()
+		for _, r := range splitTimeRange(start, end, step, maxQueryDuration) {
+			if !c.streamRange(ctx, observationQuery(loc, measures, r[0], r[1], step), loc, checker, pressures, yield) {
+				return
+			}
+		}
+	}
+}
+
+// streamRange streams the observations of a query, returning false if the
+// stream ended
+func (c *Client) streamRange(ctx context.Context, q url.Values, loc Location, checker *qualityChecker, pressures pressureHistory, yield func(Observation, error) bool) bool {
+	body, err := c.stream(ctx, q)
+	if err != nil {
+		yield(Observation{}, err)
+		return false
+	}
+	defer body.Close()
+
+	for r, err := range decodeRows(body) {
+		if err != nil {
+			yield(Observation{}, err)
+			return false
+		}
+		checker.check(&r)
+		pressures.addTendency(r)
+		obs := newObservation(r)
+		obs.Station = loc.station(obs.Station)
+		if !yield(obs, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// stream does a HTTP GET request against FMI's API with query q and
+// returns the response body to be read as it arrives
+func (c *Client) stream(ctx context.Context, q url.Values) (io.ReadCloser, error) {
+	endpoint, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.RawQuery = q.Encode()
+
+	var body io.ReadCloser
+	err = c.withRetries(ctx, func() error {
+		resp, err := c.open(ctx, endpoint.String())
+		if err != nil {
+			return err
+		}
+		body = resp.Body
+		return nil
+	})
+	return body, err
+}
+
+// decodeRows decodes a simple format response element by element, yielding
+// a row whenever the location or time of the elements changes
+func decodeRows(r io.Reader) iter.Seq2[row, error] {
+	return func(yield func(row, error) bool) {
+		d := xml.NewDecoder(r)
+		var current row
+		for {
+			tok, err := d.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				yield(row{}, decodeError(err))
+				return
+			}
+
+			start, ok := tok.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			switch start.Name.Local {
+			case "BsWfsElement":
+			case "GridSeriesObservation", "PointTimeSeriesObservation":
+				yield(row{}, fmt.Errorf("%w: vain simple-muotoa voi lukea virtana", ErrParse))
+				return
+			default:
+				continue
+			}
+
+			var obs observation
+			if err := d.DecodeElement(&obs, &start); err != nil {
+				yield(row{}, decodeError(err))
+				return
+			}
+			if current.Values != nil && (obs.Location != current.Location || !obs.Time.Equal(current.Time)) {
+				if !yield(current, nil) {
+					return
+				}
+				current = row{}
+			}
+			if current.Values == nil {
+				current = row{Location: obs.Location, Time: obs.Time, Values: make(observations)}
+			}
+			current.Values[obs.Parameter] = obs.Value
+		}
+		if current.Values != nil {
+			yield(current, nil)
+		}
+	}
+}
+
+// decodeError wraps an error decoding a response as ErrParse if the
+// response is malformed, or as ErrUnavailable if reading it failed, e.g.
+// because the connection broke or ctx was cancelled
+func decodeError(err error) error {
+	var syntaxErr *xml.SyntaxError
+	var unmarshalErr xml.UnmarshalError
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+	if errors.As(err, &syntaxErr) || errors.As(err, &unmarshalErr) || errors.As(err, &numErr) || errors.As(err, &timeErr) {
+		return fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
+
+// pressureHistory holds the sea level pressures of the last three hours
+// of each station of a stream
+type pressureHistory map[string]map[time.Time]float64
+
+// addTendency adds the pressure tendency to a row if the pressure three
+// hours earlier is known, and forgets pressures older than that
+func (h pressureHistory) addTendency(r row) {
+	p, ok := r.Values["p_sea"]
+	if !ok || math.IsNaN(p) {
+		return
+	}
+	history := h[r.Location]
+	if history == nil {
+		history = make(map[time.Time]float64)
+		h[r.Location] = history
+	}
+	earlier := r.Time.Add(-tendencyPeriod)
+	if previous, ok := history[earlier]; ok {
+		r.Values[pressureTendency] = p - previous
+	}
+	for t := range history {
+		if t.Before(earlier) {
+			delete(history, t)
+		}
+	}
+	history[r.Time] = p
+}
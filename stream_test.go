@@ -0,0 +1,140 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kari/fmi/fmitest"
+)
+
+func TestStreamTimeSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+	pressure := func(parameter string, ts time.Time) (float64, bool) {
+		switch parameter {
+		case "t2m":
+			return float64(ts.Day()), true
+		case "p_sea":
+			return 1000 + ts.Sub(start).Hours()/10, true
+		}
+		return 0, false
+	}
+	srv := fmitest.NewServer(
+		fmitest.Station{Name: "Helsinki Kaisaniemi", FMISID: 100971, Latitude: 60.17523, Longitude: 24.94459, Func: pressure},
+		fmitest.Station{Name: "Helsinki-Vantaa lentoasema", FMISID: 100968, Latitude: 60.3267, Longitude: 24.95675, Values: map[string]float64{"t2m": 1}},
+	)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithResponseFormat(SimpleFormat))
+	loc := BBox(60, 24, 61, 25)
+
+	series, err := c.TimeSeries(context.Background(), loc, start, end, time.Hour)
+	if err != nil {
+		t.Fatalf("TimeSeries() returned error %v", err)
+	}
+	want := make(map[float64][]Observation)
+	for _, s := range series {
+		want[s.Station.Latitude] = s.Observations
+	}
+
+	got := make(map[float64][]Observation)
+	for o, err := range c.StreamTimeSeries(context.Background(), loc, start, end, time.Hour) {
+		if err != nil {
+			t.Fatalf("StreamTimeSeries() returned error %v", err)
+		}
+		got[o.Station.Latitude] = append(got[o.Station.Latitude], o)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("StreamTimeSeries() mismatch with TimeSeries() (-want +got):\n%s", diff)
+	}
+	if o := got[60.17523][3*24]; !o.PressureTendency.Valid || o.PressureTendency.Value < 0.29 || o.PressureTendency.Value > 0.31 {
+		t.Errorf("StreamTimeSeries() pressure tendency = %v; want 0.3", o.PressureTendency)
+	}
+
+	n := len(srv.Requests())
+	count := 0
+	for range c.StreamTimeSeries(context.Background(), loc, start, end, time.Hour) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	if count != 5 || len(srv.Requests())-n != 1 {
+		t.Errorf("breaking out of StreamTimeSeries() after %d observations made %d requests; want 1", count, len(srv.Requests())-n)
+	}
+
+	var tests = []struct {
+		loc        Location
+		start, end time.Time
+		err        error
+	}{
+		{Location{}, start, end, ErrNoPlace},
+		{loc, end, start, ErrBadRequest},
+		{Place("Narnia"), start, end, ErrPlaceNotFound},
+	}
+	for _, test := range tests {
+		var errs []error
+		for _, err := range c.StreamTimeSeries(context.Background(), test.loc, test.start, test.end, time.Hour) {
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], test.err) {
+			t.Errorf("StreamTimeSeries(%s) returned errors %v; want %v", test.loc, errs, test.err)
+		}
+	}
+}
+
+func TestDecodeRows(t *testing.T) {
+	rows := make([]row, 0)
+	for r, err := range decodeRows(strings.NewReader(testCollection)) {
+		if err != nil {
+			t.Fatalf("decodeRows() returned error %v", err)
+		}
+		rows = append(rows, r)
+	}
+	collection, err := parseFeatureCollection([]byte(testCollection))
+	if err != nil {
+		t.Fatalf("parseFeatureCollection() returned error %v", err)
+	}
+	elements := 0
+	for _, r := range rows {
+		elements += len(r.Values)
+	}
+	if elements != len(collection.Elements) {
+		t.Errorf("decodeRows() returned %d values in %d rows; want %d", elements, len(rows), len(collection.Elements))
+	}
+
+	var errs []error
+	for _, err := range decodeRows(strings.NewReader(`<wfs:FeatureCollection><wfs:member><BsWfs:BsWfsElement>`)) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrParse) {
+		t.Errorf("decodeRows() of a truncated response returned errors %v; want ErrParse", errs)
+	}
+
+	errs = nil
+	broken := io.MultiReader(strings.NewReader(testCollection[:len(testCollection)/2]), iotest.ErrReader(context.Canceled))
+	for _, err := range decodeRows(broken) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUnavailable) || !errors.Is(errs[0], context.Canceled) || errors.Is(errs[0], ErrParse) {
+		t.Errorf("decodeRows() of an interrupted response returned errors %v; want ErrUnavailable and context.Canceled", errs)
+	}
+
+	errs = nil
+	for _, err := range decodeRows(strings.NewReader(strings.Replace(testCollection, "-2.5", "cold", 1))) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrParse) {
+		t.Errorf("decodeRows() of an invalid value returned errors %v; want ErrParse", errs)
+	}
+}
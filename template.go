@@ -0,0 +1,150 @@
+package fmi
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// TemplateData is the data passed to a Template. The observation's fields
+// and methods are available directly, e.g. {{.Temperature.Value}} and
+// {{.FeelsLike}}.
+type TemplateData struct {
+	Place string
+	Observation
+}
+
+// Template formats observations using a text/template. Besides the
+// standard functions, templates can use:
+//
+//	title          title cases a string, e.g. {{title .Place}}
+//	format         formats a value, e.g. {{format "%.1f" .Temperature}}
+//	windSpeed      describes wind speed and direction, e.g. {{windSpeed .WindSpeed .WindDirection}}
+//	windDirection  names a wind direction
+//	cloudCover     describes cloud cover
+//	humidexScale   classifies a humidex index, e.g. {{humidexScale .Humidex}}
+//	windChillScale classifies a wind chill index, e.g. {{windChillScale .WindChill}}
+//	presentWeather describes a present weather code, e.g. {{presentWeather .PresentWeather}}
+//
+// Missing values are formatted as empty strings.
+type Template struct {
+	tmpl *template.Template
+}
+
+// NewTemplate parses a template using texts in the supported language best
+// matching tag
+func NewTemplate(text string, tag language.Tag) (*Template, error) {
+	l := localeFor(tag)
+	c := cases.Title(l.tag)
+
+	funcs := template.FuncMap{
+		"title": func(s string) string {
+			return c.String(strings.ToLower(s))
+		},
+		"format": func(format string, v Value) string {
+			if !v.Valid {
+				return ""
+			}
+			return fmt.Sprintf(format, v.Value)
+		},
+		"windSpeed": func(s Value, d Value) string {
+			if !s.Valid {
+				return ""
+			}
+			if !d.Valid {
+				return l.windSpeed(s.Value, math.NaN())
+			}
+			return l.windSpeed(s.Value, d.Value)
+		},
+		"windDirection": func(d Value) string {
+			if !d.Valid {
+				return ""
+			}
+			return l.windDirection(d.Value)
+		},
+		"cloudCover": func(cc Value) string {
+			if !cc.Valid {
+				return ""
+			}
+			cover, _ := l.cloudCover(cc.Value)
+			return cover
+		},
+		"humidexScale": func(h Value) string {
+			if !h.Valid {
+				return ""
+			}
+			scale, _ := l.humidexScale(h.Value)
+			return scale
+		},
+		"windChillScale": func(w Value) string {
+			if !w.Valid {
+				return ""
+			}
+			scale, _ := l.windChillScale(w.Value)
+			return scale
+		},
+		"presentWeather": func(w Value) string {
+			if !w.Valid {
+				return ""
+			}
+			return l.presentWeathers[PresentWeather(w.Value)]
+		},
+	}
+
+	tmpl, err := template.New("weather").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute writes an observation at a place formatted with the template to w
+func (t *Template) Execute(w io.Writer, place string, o Observation) error {
+	return t.tmpl.Execute(w, TemplateData{Place: place, Observation: o})
+}
+
+// Format returns an observation at a place formatted with the template
+func (t *Template) Format(place string, o Observation) (string, error) {
+	var output strings.Builder
+	if err := t.Execute(&output, place, o); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// FeelsLike returns FMI's "feels like" temperature, see FeelsLike. It is
+// invalid when temperature, wind speed or humidity is missing.
+func (o Observation) FeelsLike() Value {
+	if !o.Temperature.Valid || !o.WindSpeed.Valid || !o.Humidity.Valid {
+		return Value{}
+	}
+	rad := math.NaN()
+	if o.Radiation.Valid {
+		rad = o.Radiation.Value
+	}
+	return Value{FeelsLike(o.Temperature.Value, o.WindSpeed.Value, o.Humidity.Value, rad), true}
+}
+
+// Humidex returns the humidity index, see Humidex. It is invalid when
+// temperature or dew point is missing.
+func (o Observation) Humidex() Value {
+	if !o.Temperature.Valid || !o.DewPoint.Valid {
+		return Value{}
+	}
+	return Value{Humidex(o.Temperature.Value, o.DewPoint.Value), true}
+}
+
+// WindChill returns the wind chill using FMI's formula, see WindChillFMI.
+// It is invalid when temperature or wind speed is missing.
+func (o Observation) WindChill() Value {
+	if !o.Temperature.Valid || !o.WindSpeed.Valid {
+		return Value{}
+	}
+	return Value{WindChillFMI(o.Temperature.Value, o.WindSpeed.Value), true}
+}
@@ -0,0 +1,57 @@
+package fmi
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestTemplate(t *testing.T) {
+	o := Observation{
+		Temperature:   Value{-22.9, true},
+		WindSpeed:     Value{15, true},
+		WindDirection: Value{45, true},
+		Humidity:      Value{20, true},
+		CloudCover:    Value{8, true},
+	}
+
+	var tests = []struct {
+		text string
+		tag  language.Tag
+		s    string
+	}{
+		{`{{title .Place}}: {{format "%.1f" .Temperature}}°C`, language.Finnish, "Turku: -22.9°C"},
+		{`{{cloudCover .CloudCover}}, {{windSpeed .WindSpeed .WindDirection}}`, language.Finnish, "pilvistä, kovaa koillistuulta"},
+		{`{{cloudCover .CloudCover}}, {{windSpeed .WindSpeed .WindGust}}`, language.English, "overcast, strong wind"},
+		{`{{format "%.1f" .FeelsLike}} ({{windChillScale .WindChill}})`, language.Swedish, "-36.5 (risk för förfrysning)"},
+		{`[{{format "%.1f" .SnowDepth}}{{humidexScale .Humidex}}{{windDirection .WindGust}}]`, language.Finnish, "[]"},
+		{`{{if .SnowDepth.Valid}}lunta{{else}}ei tietoa{{end}}`, language.Finnish, "ei tietoa"},
+	}
+	for _, test := range tests {
+		tmpl, err := NewTemplate(test.text, test.tag)
+		if err != nil {
+			t.Fatalf("NewTemplate('%s') returned error %v", test.text, err)
+		}
+		got, err := tmpl.Format("turku", o)
+		if err != nil || got != test.s {
+			t.Errorf("Format('%s') = '%s', %v; want '%s'", test.text, got, err, test.s)
+		}
+	}
+
+	if _, err := NewTemplate("{{windSpeed", language.Finnish); err == nil {
+		t.Errorf("NewTemplate() should fail on an invalid template")
+	}
+}
+
+func TestObservationIndices(t *testing.T) {
+	o := Observation{Temperature: Value{30, true}, DewPoint: Value{15, true}}
+	if h := o.Humidex(); !h.Valid || h.Value != Humidex(30, 15) {
+		t.Errorf("Humidex() = %v; want %f", h, Humidex(30, 15))
+	}
+	if f := o.FeelsLike(); f.Valid {
+		t.Errorf("FeelsLike() without wind = %v; want invalid", f)
+	}
+	if w := o.WindChill(); w.Valid {
+		t.Errorf("WindChill() without wind = %v; want invalid", w)
+	}
+}
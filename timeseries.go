@@ -0,0 +1,114 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// maxQueryDuration is the longest time range FMI serves in a single
+// observation query
+const maxQueryDuration = 168 * time.Hour
+
+// Series holds the observations of a station ordered by time
+type Series struct {
+	Station      Station
+	Observations []Observation
+}
+
+// TimeSeries returns the observations between start and end, inclusive, at
+// the given timestep for every station matching a location. Long ranges are
+// split into several requests.
+func (c *Client) TimeSeries(ctx context.Context, loc Location, start, end time.Time, step time.Duration) ([]Series, error) {
+	if !loc.valid() {
+		return nil, ErrNoPlace
+	}
+	if step < time.Minute {
+		return nil, fmt.Errorf("%w: aika-askel on alle minuutin", ErrBadRequest)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: alkuaika on loppuajan jälkeen", ErrBadRequest)
+	}
+
+	series := make([]Series, 0)
+	stations := make(map[string]int)
+	seen := make(map[string]map[time.Time]bool)
+	checker := c.newQualityChecker()
+
+	for _, r := range splitTimeRange(start, end, step, maxQueryDuration) {
+		collection, err := c.fetchFeatures(ctx, observationQuery(loc, measures, r[0], r[1], step))
+		if err != nil {
+			return nil, err
+		}
+		checker.checkCollection(&collection)
+
+		for _, row := range extractRows(collection) {
+			i, ok := stations[row.Location]
+			if !ok {
+				i = len(series)
+				stations[row.Location] = i
+				seen[row.Location] = make(map[time.Time]bool)
+				station, ok := collection.Stations[row.Location]
+				if !ok {
+					station = parseStation(row.Location)
+				}
+				series = append(series, Series{Station: loc.station(station)})
+			}
+			if seen[row.Location][row.Time] {
+				continue
+			}
+			seen[row.Location][row.Time] = true
+
+			obs := newObservation(row)
+			obs.Station = series[i].Station
+			series[i].Observations = append(series[i].Observations, obs)
+		}
+	}
+
+	if len(series) == 0 {
+		return nil, ErrNoData
+	}
+
+	for _, s := range series {
+		slices.SortFunc(s.Observations, func(a, b Observation) int {
+			return a.Time.Compare(b.Time)
+		})
+		addPressureTendencies(s.Observations)
+	}
+
+	return series, nil
+}
+
+// splitTimeRange splits the range from start to end into consecutive
+// ranges no longer than max. Ranges are inclusive, so the next range
+// starts one step after the previous one ends.
+func splitTimeRange(start, end time.Time, step, max time.Duration) [][2]time.Time {
+	ranges := make([][2]time.Time, 0)
+	for !start.After(end) {
+		rangeEnd := start.Add(max)
+		if rangeEnd.After(end) {
+			rangeEnd = end
+		}
+		ranges = append(ranges, [2]time.Time{start, rangeEnd})
+		start = rangeEnd.Add(step)
+	}
+	return ranges
+}
+
+// extractRows returns every row of a collection ordered by station and
+// then by time
+func extractRows(collection simpleFeatureCollection) []row {
+	times, locations, observations := groupElements(collection)
+	slices.Reverse(times)
+
+	rows := make([]row, 0, len(times)*len(locations))
+	for _, locationIndex := range locations {
+		for _, timeIndex := range times {
+			if obs, ok := observations[timeIndex][locationIndex]; ok {
+				rows = append(rows, row{Location: locationIndex, Time: timeIndex, Values: obs, Quality: collection.Quality[locationIndex][timeIndex]})
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,87 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		end    time.Time
+		ranges [][2]time.Time
+	}{
+		{start, [][2]time.Time{{start, start}}},
+		{start.Add(time.Hour), [][2]time.Time{{start, start.Add(time.Hour)}}},
+		{start.Add(25 * time.Hour), [][2]time.Time{
+			{start, start.Add(12 * time.Hour)},
+			{start.Add(13 * time.Hour), start.Add(25 * time.Hour)},
+		}},
+		{start.Add(-time.Hour), [][2]time.Time{}},
+	}
+	for _, test := range tests {
+		got := splitTimeRange(start, test.end, time.Hour, 12*time.Hour)
+		if diff := cmp.Diff(test.ranges, got); diff != "" {
+			t.Errorf("splitTimeRange(%v) mismatch (-want +got):\n%s", test.end, diff)
+		}
+	}
+}
+
+func TestTimeSeries(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Answer with a temperature at both ends of the requested range
+		start, _ := time.Parse(time.RFC3339, r.URL.Query().Get("starttime"))
+		end, _ := time.Parse(time.RFC3339, r.URL.Query().Get("endtime"))
+		fmt.Fprint(w, `<wfs:FeatureCollection numberMatched="2" numberReturned="2">`)
+		for _, ts := range []time.Time{end, start} {
+			fmt.Fprintf(w, `<wfs:member><BsWfs:BsWfsElement>
+				<BsWfs:Location><gml:Point><gml:pos>60.17523 24.94459 </gml:pos></gml:Point></BsWfs:Location>
+				<BsWfs:Time>%s</BsWfs:Time>
+				<BsWfs:ParameterName>t2m</BsWfs:ParameterName>
+				<BsWfs:ParameterValue>%d</BsWfs:ParameterValue>
+				</BsWfs:BsWfsElement></wfs:member>`, ts.Format(time.RFC3339), ts.Day())
+		}
+		fmt.Fprint(w, `</wfs:FeatureCollection>`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+
+	series, err := c.TimeSeries(context.Background(), FMISID(100971), start, end, time.Hour)
+	if err != nil {
+		t.Fatalf("TimeSeries() returned error %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("TimeSeries() made %d requests; want 2", requests)
+	}
+	if len(series) != 1 || series[0].Station.FMISID != 100971 {
+		t.Fatalf("TimeSeries() = %+v; want a single station 100971", series)
+	}
+
+	obs := series[0].Observations
+	if len(obs) != 4 {
+		t.Fatalf("TimeSeries() returned %d observations; want 4", len(obs))
+	}
+	for i := 1; i < len(obs); i++ {
+		if !obs[i-1].Time.Before(obs[i].Time) {
+			t.Errorf("TimeSeries() observations are not ordered by time: %v, %v", obs[i-1].Time, obs[i].Time)
+		}
+	}
+	if !obs[0].Time.Equal(start) || !obs[3].Time.Equal(end) || obs[3].Temperature.Value != 11 {
+		t.Errorf("TimeSeries() = %v .. %v; want %v .. %v", obs[0].Time, obs[3].Time, start, end)
+	}
+
+	if _, err := c.TimeSeries(context.Background(), FMISID(100971), end, start, time.Hour); err == nil {
+		t.Errorf("TimeSeries() with end before start should return an error")
+	}
+}
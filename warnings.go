@@ -0,0 +1,386 @@
+package fmi
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Severity is the severity of a weather warning
+type Severity int
+
+// Severities as defined by CAP, the comments give FMI's colours
+const (
+	SeverityUnknown Severity = iota
+	SeverityMinor
+	SeverityModerate // yellow
+	SeveritySevere   // orange
+	SeverityExtreme  // red
+)
+
+// String returns FMI's colour for the severity in Finnish
+func (s Severity) String() string {
+	switch s {
+	case SeverityMinor:
+		return "vihreä"
+	case SeverityModerate:
+		return "keltainen"
+	case SeveritySevere:
+		return "oranssi"
+	case SeverityExtreme:
+		return "punainen"
+	}
+	return "tuntematon"
+}
+
+func parseSeverity(s string) Severity {
+	switch s {
+	case "Minor":
+		return SeverityMinor
+	case "Moderate":
+		return SeverityModerate
+	case "Severe":
+		return SeveritySevere
+	case "Extreme":
+		return SeverityExtreme
+	}
+	return SeverityUnknown
+}
+
+// WarningText holds the texts of a warning in one language
+type WarningText struct {
+	Event       string
+	Headline    string
+	Description string
+	Areas       []string
+}
+
+// Point is a geographic coordinate
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Warning is a weather warning issued by FMI
+type Warning struct {
+	ID       string
+	Type     string // event code, e.g. "wind"
+	Severity Severity
+	Onset    time.Time
+	Expires  time.Time
+	Areas    [][]Point // polygons covered by the warning
+	Texts    map[language.Tag]WarningText
+}
+
+// Text returns the warning's texts in the language best matching tag,
+// falling back to Finnish
+func (w Warning) Text(tag language.Tag) WarningText {
+	if len(w.Texts) == 0 {
+		return WarningText{}
+	}
+	// The first tag is the matcher's fallback, so Finnish goes first
+	finnish := make([]language.Tag, 0, 1)
+	others := make([]language.Tag, 0, len(w.Texts))
+	for t := range w.Texts {
+		if base, _ := t.Base(); base.String() == "fi" {
+			finnish = append(finnish, t)
+		} else {
+			others = append(others, t)
+		}
+	}
+	slices.SortFunc(others, func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	languages := append(finnish, others...)
+	_, i, _ := language.NewMatcher(languages).Match(tag)
+	return w.Texts[languages[i]]
+}
+
+// Covers reports whether the warning's area covers a coordinate
+func (w Warning) Covers(lat, lon float64) bool {
+	for _, polygon := range w.Areas {
+		if polygonContains(polygon, Point{lat, lon}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mentions reports whether a place is named in the warning's areas,
+// ignoring case. The place must match whole words of an area, so that
+// e.g. Ii does not match Iisalmi.
+func (w Warning) Mentions(place string) bool {
+	words := nameWords(place)
+	if len(words) == 0 {
+		return false
+	}
+	for _, text := range w.Texts {
+		for _, area := range text.Areas {
+			areaWords := nameWords(area)
+			for i := 0; i+len(words) <= len(areaWords); i++ {
+				if slices.Equal(areaWords[i:i+len(words)], words) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// nameWords splits a place or area name into lower case words, e.g.
+// "Etelä-Pohjanmaa" into "etelä" and "pohjanmaa"
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// atomFeed is FMI's warnings feed with CAP alerts as entry content
+type atomFeed struct {
+	Entries []struct {
+		Alerts []capAlert `xml:"content>alert"`
+	} `xml:"entry"`
+}
+
+type capAlert struct {
+	Identifier string    `xml:"identifier"`
+	MsgType    string    `xml:"msgType"`
+	Infos      []capInfo `xml:"info"`
+}
+
+type capInfo struct {
+	Language   string `xml:"language"`
+	Event      string `xml:"event"`
+	EventCodes []struct {
+		Name  string `xml:"valueName"`
+		Value string `xml:"value"`
+	} `xml:"eventCode"`
+	Severity    string    `xml:"severity"`
+	Onset       time.Time `xml:"onset"`
+	Expires     time.Time `xml:"expires"`
+	Headline    string    `xml:"headline"`
+	Description string    `xml:"description"`
+	Areas       []struct {
+		Description string   `xml:"areaDesc"`
+		Polygons    []string `xml:"polygon"`
+	} `xml:"area"`
+}
+
+// Warnings returns the weather warnings currently in effect or upcoming
+func (c *Client) Warnings(ctx context.Context) ([]Warning, error) {
+	body, err := c.fetch(ctx, c.warningsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := parseWarnings(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := make([]Warning, 0, len(warnings))
+	for _, w := range warnings {
+		if w.Expires.IsZero() || w.Expires.After(now) {
+			active = append(active, w)
+		}
+	}
+
+	return active, nil
+}
+
+// WarningsFor returns the warnings whose areas mention a place
+func (c *Client) WarningsFor(ctx context.Context, place string) ([]Warning, error) {
+	if place == "" {
+		return nil, ErrNoPlace
+	}
+
+	warnings, err := c.Warnings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]Warning, 0)
+	for _, w := range warnings {
+		if w.Mentions(place) {
+			matching = append(matching, w)
+		}
+	}
+	return matching, nil
+}
+
+// WarningsAt returns the warnings covering a coordinate
+func (c *Client) WarningsAt(ctx context.Context, lat, lon float64) ([]Warning, error) {
+	warnings, err := c.Warnings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]Warning, 0)
+	for _, w := range warnings {
+		if w.Covers(lat, lon) {
+			matching = append(matching, w)
+		}
+	}
+	return matching, nil
+}
+
+// WarningSummary returns the warnings for a place as a written description
+func (c *Client) WarningSummary(ctx context.Context, place string) (string, error) {
+	tz, err := helsinki()
+	if err != nil {
+		return "", err
+	}
+	warnings, err := c.WarningsFor(ctx, place)
+	if err != nil {
+		return "", err
+	}
+
+	return formatWarnings(place, warnings, tz), nil
+}
+
+func parseWarnings(data []byte) ([]Warning, error) {
+	var feed atomFeed
+
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	warnings := make([]Warning, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		for _, alert := range entry.Alerts {
+			if alert.MsgType == "Cancel" || len(alert.Infos) == 0 {
+				continue
+			}
+			warnings = append(warnings, newWarning(alert))
+		}
+	}
+
+	return warnings, nil
+}
+
+// newWarning converts a CAP alert to a Warning. Language independent
+// fields are taken from the first info block.
+func newWarning(alert capAlert) Warning {
+	info := alert.Infos[0]
+	w := Warning{
+		ID:       alert.Identifier,
+		Severity: parseSeverity(info.Severity),
+		Onset:    info.Onset,
+		Expires:  info.Expires,
+		Texts:    make(map[language.Tag]WarningText),
+	}
+
+	for _, code := range info.EventCodes {
+		if w.Type == "" || code.Name == "eventType" {
+			w.Type = code.Value
+		}
+	}
+
+	for _, area := range info.Areas {
+		for _, polygon := range area.Polygons {
+			if points := parsePolygon(polygon); len(points) > 2 {
+				w.Areas = append(w.Areas, points)
+			}
+		}
+	}
+
+	for _, info := range alert.Infos {
+		tag, err := language.Parse(info.Language)
+		if err != nil {
+			continue
+		}
+		text := WarningText{
+			Event:       info.Event,
+			Headline:    info.Headline,
+			Description: info.Description,
+		}
+		for _, area := range info.Areas {
+			text.Areas = append(text.Areas, area.Description)
+		}
+		w.Texts[tag] = text
+	}
+
+	return w
+}
+
+// parsePolygon parses a CAP polygon of space separated "lat,lon" pairs
+func parsePolygon(s string) []Point {
+	points := make([]Point, 0)
+	for _, pair := range strings.Fields(s) {
+		lat, lon, ok := strings.Cut(pair, ",")
+		if !ok {
+			return nil
+		}
+		p := Point{}
+		var err error
+		if p.Latitude, err = strconv.ParseFloat(lat, 64); err != nil {
+			return nil
+		}
+		if p.Longitude, err = strconv.ParseFloat(lon, 64); err != nil {
+			return nil
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// polygonContains reports whether p is inside polygon using ray casting
+func polygonContains(polygon []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// formatWarnings returns a string representation of the warnings at a
+// place with times in time zone tz
+func formatWarnings(place string, warnings []Warning, tz *time.Location) string {
+	var output strings.Builder
+
+	c := cases.Title(language.Finnish)
+
+	fmt.Fprintf(&output, "Varoitukset paikassa %s: ", c.String(strings.ToLower(place)))
+	if len(warnings) == 0 {
+		output.WriteString("ei voimassa olevia varoituksia")
+		return output.String()
+	}
+
+	for i, w := range warnings {
+		if i > 0 {
+			output.WriteString("; ")
+		}
+		fmt.Fprintf(&output, "%s (%s)", w.Text(language.Finnish).Event, w.Severity)
+		if validity := formatValidity(w.Onset, w.Expires, tz); validity != "" {
+			fmt.Fprintf(&output, " %s", validity)
+		}
+	}
+
+	return output.String()
+}
+
+// formatValidity returns a warning's validity window in time zone tz,
+// e.g. "12.5. klo 14–18"
+func formatValidity(onset, expires time.Time, tz *time.Location) string {
+	if onset.IsZero() || expires.IsZero() {
+		return ""
+	}
+	onset, expires = onset.In(tz), expires.In(tz)
+	if dayOf(onset, tz).Equal(dayOf(expires, tz)) {
+		return fmt.Sprintf("%d.%d. klo %d–%d", onset.Day(), onset.Month(), onset.Hour(), expires.Hour())
+	}
+	return fmt.Sprintf("%d.%d. klo %d – %d.%d. klo %d", onset.Day(), onset.Month(), onset.Hour(), expires.Day(), expires.Month(), expires.Hour())
+}
@@ -0,0 +1,180 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+const testWarningsFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>urn:oid:1</id>
+    <content type="application/cap+xml">
+      <alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+        <identifier>2.49.0.1.246.0.0.2099.1</identifier>
+        <msgType>Alert</msgType>
+        <info>
+          <language>fi-FI</language>
+          <event>Tuulivaroitus maa-alueille</event>
+          <eventCode><valueName>eventType</valueName><value>wind</value></eventCode>
+          <severity>Moderate</severity>
+          <onset>2099-05-12T14:00:00+03:00</onset>
+          <expires>2099-05-12T18:00:00+03:00</expires>
+          <headline>Keltainen tuulivaroitus</headline>
+          <description>Puuskissa 20 m/s.</description>
+          <area><areaDesc>Helsinki</areaDesc><polygon>60.0,24.5 60.0,25.5 60.5,25.5 60.5,24.5 60.0,24.5</polygon></area>
+        </info>
+        <info>
+          <language>en-GB</language>
+          <event>Wind warning for land areas</event>
+          <severity>Moderate</severity>
+          <headline>Yellow wind warning</headline>
+          <area><areaDesc>Helsinki</areaDesc></area>
+        </info>
+      </alert>
+    </content>
+  </entry>
+  <entry>
+    <content type="application/cap+xml">
+      <alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+        <identifier>2.49.0.1.246.0.0.2000.1</identifier>
+        <msgType>Alert</msgType>
+        <info>
+          <language>fi-FI</language>
+          <event>Maastopalovaroitus</event>
+          <severity>Severe</severity>
+          <onset>2000-05-12T14:00:00+03:00</onset>
+          <expires>2000-05-12T18:00:00+03:00</expires>
+          <area><areaDesc>Lappi</areaDesc></area>
+        </info>
+      </alert>
+    </content>
+  </entry>
+</feed>`
+
+func TestParseWarnings(t *testing.T) {
+	warnings, err := parseWarnings([]byte(testWarningsFeed))
+	if err != nil || len(warnings) != 2 {
+		t.Fatalf("parseWarnings() = %v, %v; want 2 warnings", warnings, err)
+	}
+
+	w := warnings[0]
+	if w.Type != "wind" || w.Severity != SeverityModerate || len(w.Areas) != 1 {
+		t.Errorf("parseWarnings() = %+v; want a moderate wind warning with one area", w)
+	}
+	if got := w.Text(language.English).Event; got != "Wind warning for land areas" {
+		t.Errorf("Text(en).Event = '%s'; want 'Wind warning for land areas'", got)
+	}
+	if got := w.Text(language.Swedish).Headline; got != "Keltainen tuulivaroitus" {
+		t.Errorf("Text(sv).Headline = '%s'; want Finnish fallback", got)
+	}
+	if !w.Covers(60.17, 24.94) || w.Covers(61.5, 23.8) {
+		t.Errorf("Covers() should match Helsinki and not Tampere")
+	}
+	if !w.Mentions("helsinki") || w.Mentions("Turku") {
+		t.Errorf("Mentions() should match Helsinki and not Turku")
+	}
+
+	if _, err := parseWarnings([]byte("<feed")); err == nil {
+		t.Errorf("parseWarnings() should fail on invalid XML")
+	}
+}
+
+func TestWarningMentions(t *testing.T) {
+	w := Warning{Texts: map[language.Tag]WarningText{
+		language.Finnish: {Areas: []string{"Iisalmi", "Salon seutu", "Etelä-Pohjanmaa", "Merialueet: Pohjois-Itämeri"}},
+	}}
+	var tests = []struct {
+		place string
+		want  bool
+	}{
+		{"Iisalmi", true},
+		{"iisalmi", true},
+		{"Ii", false},
+		{"Salo", false},
+		{"salon seutu", true},
+		{"Pohjanmaa", true},
+		{"Etelä-Pohjanmaa", true},
+		{"Pohjois-Itämeri", true},
+		{"Itä", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := w.Mentions(test.place); got != test.want {
+			t.Errorf("Mentions(%q) = %t; want %t", test.place, got, test.want)
+		}
+	}
+}
+
+func TestFormatWarnings(t *testing.T) {
+	onset := time.Date(2024, 5, 12, 14, 0, 0, 0, finnishTime)
+	var tests = []struct {
+		warnings []Warning
+		s        string
+	}{
+		{nil, "Varoitukset paikassa Helsinki: ei voimassa olevia varoituksia"},
+		{[]Warning{{
+			Severity: SeverityModerate,
+			Onset:    onset,
+			Expires:  onset.Add(4 * time.Hour),
+			Texts:    map[language.Tag]WarningText{language.Finnish: {Event: "Tuulivaroitus"}},
+		}, {
+			Severity: SeveritySevere,
+			Onset:    onset,
+			Expires:  onset.Add(20 * time.Hour),
+			Texts:    map[language.Tag]WarningText{language.Finnish: {Event: "Liikennesää"}},
+		}}, "Varoitukset paikassa Helsinki: Tuulivaroitus (keltainen) 12.5. klo 14–18; Liikennesää (oranssi) 12.5. klo 14 – 13.5. klo 10"},
+	}
+	for _, test := range tests {
+		got := formatWarnings("helsinki", test.warnings, finnishTime)
+		if got != test.s {
+			t.Errorf("got '%s', wanted '%s'", got, test.s)
+		}
+	}
+}
+
+func TestClientWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testWarningsFeed))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithWarningsURL(srv.URL))
+	warnings, err := c.Warnings(context.Background())
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("Warnings() = %v, %v; want only the unexpired warning", warnings, err)
+	}
+
+	s, err := c.WarningSummary(context.Background(), "Helsinki")
+	if err != nil || s != "Varoitukset paikassa Helsinki: Tuulivaroitus maa-alueille (keltainen) 12.5. klo 14–18" {
+		t.Errorf("WarningSummary('Helsinki') = '%s', %v", s, err)
+	}
+
+	warnings, err = c.WarningsAt(context.Background(), 65.0, 25.5)
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("WarningsAt(Oulu) = %v, %v; want no warnings", warnings, err)
+	}
+}
+
+func TestWarningTextFallback(t *testing.T) {
+	w := Warning{Texts: map[language.Tag]WarningText{
+		language.English:            {Event: "en"},
+		language.MustParse("sv-FI"): {Event: "sv"},
+		language.MustParse("fi-FI"): {Event: "fi"},
+		language.MustParse("en-GB"): {Event: "en-GB"},
+		language.MustParse("se"):    {Event: "se"},
+	}}
+	for range 10 {
+		if got := w.Text(language.German).Event; got != "fi" {
+			t.Fatalf("Text(de).Event = '%s'; want the Finnish fallback", got)
+		}
+	}
+	if got := w.Text(language.Swedish).Event; got != "sv" {
+		t.Errorf("Text(sv).Event = '%s'; want 'sv'", got)
+	}
+}